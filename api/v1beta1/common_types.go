@@ -16,6 +16,13 @@ limitations under the License.
 
 package v1beta1
 
+import (
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
 // Hash - struct to add hashes to status
 type Hash struct {
 	// Name of hash referencing the parameter
@@ -23,3 +30,201 @@ type Hash struct {
 	// Hash
 	Hash string `json:"hash,omitempty"`
 }
+
+// PasswordSelector - key names of the credentials in the referenced secrets
+type PasswordSelector struct {
+	// Key in the NovaSecret holding the nova database password, defaults to DatabasePassword
+	Database string `json:"database,omitempty"`
+	// Key in the DatabaseAdminSecret holding the root password of the DB server, defaults to DbRootPassword
+	DatabaseAdmin string `json:"databaseAdmin,omitempty"`
+	// Key in the NovaSecret holding the nova keystone password, defaults to NovaKeystoneAuthPassword
+	Service string `json:"service,omitempty"`
+	// Key in the PlacementSecret holding the placement keystone password, defaults to PlacementKeystoneAuthPassword
+	Placement string `json:"placement,omitempty"`
+	// Key in the NeutronSecret holding the neutron keystone password, defaults to NeutronKeystoneAuthPassword
+	Neutron string `json:"neutron,omitempty"`
+	// Key in the TransportURLSecret holding the transport url, defaults to TransportUrl
+	TransportURL string `json:"transportURL,omitempty"`
+	// Key in the NovaSecret holding the optional metadata proxy shared secret, defaults to MetadataProxySharedSecret
+	Metadata string `json:"metadata,omitempty"`
+	// Key in the ApplicationCredentialSecret holding the application credential id, defaults to ApplicationCredentialID
+	ApplicationCredentialID string `json:"applicationCredentialID,omitempty"`
+	// Key in the ApplicationCredentialSecret holding the application credential secret, defaults to ApplicationCredentialSecret
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
+}
+
+// DatabaseTLS - TLS settings of the database connections
+type DatabaseTLS struct {
+	// Secret containing the CA certificate of the database server in the ca.crt key, required if Mode is set
+	CASecret string `json:"caSecret,omitempty"`
+	// TLS mode, require encrypts the connections and verifies the certificate chain,
+	// verify additionally verifies the server hostname. TLS is disabled if not set.
+	// +kubebuilder:validation:Enum=require;verify
+	Mode string `json:"mode,omitempty"`
+}
+
+// MessagingBus - RabbitMQ cluster to create a vhost and user on and derive the transport url from
+type MessagingBus struct {
+	// Name of the RabbitmqCluster
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Notifications - oslo.messaging notifications emitted by the nova services
+type Notifications struct {
+	// Emit notifications, the noop driver is used if not enabled
+	Enabled bool `json:"enabled,omitempty"`
+	// Format of the emitted notifications, defaults to unversioned
+	// +kubebuilder:validation:Enum=unversioned;versioned;both
+	Format string `json:"format,omitempty"`
+	// Topics to emit the notifications on, defaults to notifications
+	Topics []string `json:"topics,omitempty"`
+	// Secret containing the transport url of the notifications bus, defaults to the RPC transport url
+	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+}
+
+// Policy - API policy overrides, either inline or from the policy.yaml key of a ConfigMap
+type Policy struct {
+	// Inline policy.yaml
+	Inline string `json:"inline,omitempty"`
+	// Name of the ConfigMap with the policy.yaml key, takes precedence over the inline policy
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// Probe - timing and thresholds of a container probe, unset fields use the defaults of the service
+type Probe struct {
+	// Seconds after the container started before the probe is initiated
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// Seconds between two probes
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// Seconds after which the probe times out
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// Consecutive failures for the probe to be considered failed
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// Probes - probes of a service container
+type Probes struct {
+	// Restarts the container if it fails
+	Liveness Probe `json:"liveness,omitempty"`
+	// Removes the pod from the Service endpoints while it fails
+	Readiness Probe `json:"readiness,omitempty"`
+	// Holds back the liveness and readiness probes until it succeeds once, tolerates slow first boots
+	Startup Probe `json:"startup,omitempty"`
+}
+
+// Placement - scheduling constraints of the pods of a control plane service
+type Placement struct {
+	// Labels of the nodes the pods can run on
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the pods
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity of the pods, defaults to a soft anti-affinity between the replicas across nodes and zones
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Constraints to spread the pods across topology domains
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// ComputePlacement - nodes the daemons of the compute nodes run on, in addition to the RoleName. One of the
+// RoleName, NodeSelector or NodeAffinity is required to keep the daemons off the control plane nodes
+type ComputePlacement struct {
+	// Labels of the nodes the pods run on
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Node affinity of the pods
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+	// Tolerations of the pods
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// PodDisruptionBudget - disruption budget of the pods of a service with more than one replica,
+// defaults to a maxUnavailable of 1
+type PodDisruptionBudget struct {
+	// Number or percentage of pods which must stay available during an eviction, takes precedence over MaxUnavailable
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Number or percentage of pods which can be unavailable during an eviction
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// Autoscaling - horizontal pod autoscaling of a service, the Replicas are only the initial
+// number of replicas while it is enabled
+type Autoscaling struct {
+	// Create a HorizontalPodAutoscaler for the service
+	Enabled bool `json:"enabled,omitempty"`
+	// Lower limit of the replicas, defaults to the Replicas
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// Upper limit of the replicas
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+	// Target average CPU utilization in percent of the requested CPU, defaults to 80 without Metrics
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// Custom metrics to scale on, the TargetCPUUtilizationPercentage gets added if set
+	Metrics []autoscalingv2beta2.MetricSpec `json:"metrics,omitempty"`
+}
+
+// ConditionType - type of a status condition
+type ConditionType string
+
+const (
+	// DbSyncCondition - state of the DB sync job
+	DbSyncCondition ConditionType = "DbSync"
+	// CreateCellCondition - state of the create cell job
+	CreateCellCondition ConditionType = "CreateCell"
+	// DatabaseAccountsCondition - state of the DB accounts job
+	DatabaseAccountsCondition ConditionType = "DatabaseAccounts"
+	// SecretsCondition - state of the referenced secrets
+	SecretsCondition ConditionType = "Secrets"
+	// PolicyCondition - state of the API policy overrides
+	PolicyCondition ConditionType = "Policy"
+	// APIEndpointCondition - state of the API endpoint check
+	APIEndpointCondition ConditionType = "APIEndpoint"
+	// ComputeStackCondition - rollout state of the daemons of a compute stack
+	ComputeStackCondition ConditionType = "ComputeStack"
+	// PlacementCondition - state of the placement of the compute daemons
+	PlacementCondition ConditionType = "Placement"
+)
+
+const (
+	// JobRunningReason - the job is still running
+	JobRunningReason = "JobRunning"
+	// JobCompletedReason - the job completed successfully
+	JobCompletedReason = "JobCompleted"
+	// JobFailedReason - the job failed and exhausted its retries
+	JobFailedReason = "JobFailed"
+	// SecretsValidReason - all referenced secrets contain the required keys
+	SecretsValidReason = "SecretsValid"
+	// SecretMissingKeyReason - a referenced secret is missing a required key
+	SecretMissingKeyReason = "SecretMissingKey"
+	// SecretNotSetReason - a secret required by another parameter is not set
+	SecretNotSetReason = "SecretNotSet"
+	// PolicyValidReason - the policy overrides are valid YAML
+	PolicyValidReason = "PolicyValid"
+	// PolicyInvalidReason - the policy overrides are missing or no valid YAML
+	PolicyInvalidReason = "PolicyInvalid"
+	// APIEndpointReadyReason - the API endpoint responds
+	APIEndpointReadyReason = "APIEndpointReady"
+	// APIEndpointUnavailableReason - the API endpoint does not respond or returns an error
+	APIEndpointUnavailableReason = "APIEndpointUnavailable"
+	// ComputeStackReadyReason - all daemons of the compute stack are ready on their nodes
+	ComputeStackReadyReason = "ComputeStackReady"
+	// ComputeStackProgressingReason - daemons of the compute stack are not ready on all nodes yet
+	ComputeStackProgressingReason = "ComputeStackProgressing"
+	// PlacementValidReason - the compute daemons are restricted to the compute nodes
+	PlacementValidReason = "PlacementValid"
+	// PlacementInvalidReason - the compute daemons would run on all nodes
+	PlacementInvalidReason = "PlacementInvalid"
+)
+
+// Condition - struct to add conditions to status
+type Condition struct {
+	// Type of the condition
+	Type ConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// Reason for the last transition in CamelCase
+	Reason string `json:"reason,omitempty"`
+	// Message with details about the last transition
+	Message string `json:"message,omitempty"`
+	// Last time the condition transitioned from one status to another
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
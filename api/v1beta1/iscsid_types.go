@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,8 +25,15 @@ import (
 type IscsidSpec struct {
 	// Image is the Docker image to run for the daemon
 	IscsidImage string `json:"iscsidImage"`
-	// Name of the worker role created for OSP computes
-	RoleName string `json:"roleName"`
+	// Name of the worker role created for OSP computes, shorthand for the node-role.kubernetes.io/<RoleName>
+	// node selector and the dedicated=<RoleName> toleration
+	RoleName string `json:"roleName,omitempty"`
+	// Node selector, node affinity and tolerations of the pods
+	ComputePlacement `json:",inline"`
+	// Compute resources of the iscsid and init containers
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Priority class of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // IscsidStatus defines the observed state of Iscsid
@@ -34,6 +42,8 @@ type IscsidStatus struct {
 	Count int32 `json:"count"`
 	// hashes of Secrets, CMs
 	Hashes []Hash `json:"hashes,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,6 +27,13 @@ type NovaSpec struct {
 	Cell string `json:"cell,omitempty"`
 	// Nova Database Hostname String
 	DatabaseHostname string `json:"databaseHostname,omitempty"`
+	// TLS settings of the database connections
+	DatabaseTLS DatabaseTLS `json:"databaseTLS,omitempty"`
+	// Secret containing the root password of the DB server, used by the DB accounts job
+	// to create the DB accounts of the services
+	DatabaseAdminSecret string `json:"databaseAdminSecret,omitempty"`
+	// Name of the Memcached instance to cache keystone tokens and API data in
+	MemcachedInstance string `json:"memcachedInstance,omitempty"`
 	// Nova API Container Image URL
 	NovaAPIContainerImage string `json:"novaAPIContainerImage,omitempty"`
 	// Nova Scheduler Container Image URL
@@ -34,18 +42,65 @@ type NovaSpec struct {
 	NovaConductorContainerImage string `json:"novaConductorContainerImage,omitempty"`
 	// Nova API Replicas
 	NovaAPIReplicas int32 `json:"novaAPIReplicas"`
+	// Nova API compute resources
+	NovaAPIResources corev1.ResourceRequirements `json:"novaAPIResources,omitempty"`
+	// Nova Scheduler compute resources
+	NovaSchedulerResources corev1.ResourceRequirements `json:"novaSchedulerResources,omitempty"`
+	// Nova Conductor compute resources of the super conductor
+	NovaConductorResources corev1.ResourceRequirements `json:"novaConductorResources,omitempty"`
+	// Compute resources of the db sync and create cell jobs
+	JobResources corev1.ResourceRequirements `json:"jobResources,omitempty"`
+	// Priority class of the pods of all services and jobs
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Node placement of the pods of all control plane services
+	Placement Placement `json:"placement,omitempty"`
+	// Disruption budget of the pods of all control plane services
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+	// Nova API httpd/mod_wsgi process model
+	NovaAPIWSGI NovaAPIWSGI `json:"novaAPIWSGI,omitempty"`
+	// Nova API horizontal pod autoscaling
+	NovaAPIAutoscaling Autoscaling `json:"novaAPIAutoscaling,omitempty"`
+	// Nova API probe thresholds
+	NovaAPIProbes Probes `json:"novaAPIProbes,omitempty"`
+	// Verify the API endpoint with an authenticated GET /os-services of the nova service user
+	// in addition to the version discovery before reporting it in the status
+	VerifyAPIServices bool `json:"verifyAPIServices,omitempty"`
+	// Keystone endpoint the nova service user, or the application credential if set, authenticates against
+	// to verify the API services, defaults to http://keystone.openstack.svc:5000/v3
+	KeystoneAuthURL string `json:"keystoneAuthURL,omitempty"`
+	// Nova Scheduler health check probe thresholds
+	NovaSchedulerProbes Probes `json:"novaSchedulerProbes,omitempty"`
+	// Nova Conductor health check probe thresholds of the super and cell conductors
+	NovaConductorProbes Probes `json:"novaConductorProbes,omitempty"`
 	// Nova Scheduler Replicas
 	NovaSchedulerReplicas int32 `json:"novaSchedulerReplicas"`
 	// Nova Conductor Replicas
 	NovaConductorReplicas int32 `json:"novaConductorReplicas"`
 	// Secret containing: NovaPassword, TransportURL
+	// Pointing it to a new secret version rotates the DB password
 	NovaSecret string `json:"novaSecret,omitempty"`
 	// Secret containing: PlacementPassword
 	PlacementSecret string `json:"placementSecret,omitempty"`
 	// Secret containing: NeutronPassword
 	NeutronSecret string `json:"neutronSecret,omitempty"`
+	// Secret containing: ApplicationCredentialID, ApplicationCredentialSecret, the nova service
+	// authenticates with the application credential instead of the NovaKeystoneAuthPassword if set
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
 	// Secret containing: cell transport_url
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// RabbitMQ cluster to derive the transport url from instead of the TransportURLSecret
+	MessagingBus MessagingBus `json:"messagingBus,omitempty"`
+	// Notifications emitted by the services
+	Notifications Notifications `json:"notifications,omitempty"`
+	// Key names of the credentials in the referenced secrets
+	PasswordSelectors PasswordSelector `json:"passwordSelectors,omitempty"`
+	// API policy overrides of the api and metadata services
+	Policy Policy `json:"policy,omitempty"`
+	// Generate the NovaSecret with random passwords if it does not exist
+	GenerateNovaSecret bool `json:"generateNovaSecret,omitempty"`
+	// Number of retries before the db sync and create cell jobs are
+	// considered failed, defaults to 6
+	JobRetryLimit int32 `json:"jobRetryLimit,omitempty"`
 	// Cells to create
 	Cells []Cell `json:"cells,omitempty"`
 }
@@ -56,8 +111,13 @@ type Cell struct {
 	Name string `json:"name,omitempty"`
 	// Hostname of Cell DB server, if not provided same as NovaSpec DatabaseHostname
 	DatabaseHostname string `json:"databaseHostname,omitempty"`
+	// Secret containing the root password of the cell DB server, if not provided same as NovaSpec DatabaseAdminSecret
+	DatabaseAdminSecret string `json:"databaseAdminSecret,omitempty"`
 	// Name of secret which provides the cell transport url
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// RabbitMQ cluster to derive the cell transport url from instead of the TransportURLSecret,
+	// defaults to the Nova messagingBus if neither is set
+	MessagingBus MessagingBus `json:"messagingBus,omitempty"`
 	// Nova Conductor Container Image URL
 	NovaConductorContainerImage string `json:"novaConductorContainerImage,omitempty"`
 	// Nova Metadata Container Image URL
@@ -70,6 +130,14 @@ type Cell struct {
 	NovaMetadataReplicas int32 `json:"novaMetadataReplicas"`
 	// Nova NoVNC Replicas
 	NovaNoVNCProxyReplicas int32 `json:"novaNoVNCProxyReplicas"`
+	// Nova Conductor compute resources
+	NovaConductorResources corev1.ResourceRequirements `json:"novaConductorResources,omitempty"`
+	// Nova Metadata compute resources
+	NovaMetadataResources corev1.ResourceRequirements `json:"novaMetadataResources,omitempty"`
+	// Nova NoVNC compute resources
+	NovaNoVNCProxyResources corev1.ResourceRequirements `json:"novaNoVNCProxyResources,omitempty"`
+	// Nova Metadata horizontal pod autoscaling
+	NovaMetadataAutoscaling Autoscaling `json:"novaMetadataAutoscaling,omitempty"`
 }
 
 // NovaStatus defines the observed state of Nova
@@ -80,6 +148,12 @@ type NovaStatus struct {
 	DbSyncStatus string `json:"dbSyncStatus"`
 	// API endpoint
 	APIEndpoint string `json:"apiEndpoint"`
+	// NovaSecret used by the DB accounts and services, differs from the spec during a DB password rotation
+	DatabaseSecret string `json:"databaseSecret,omitempty"`
+	// DatabaseAccountsHash DB accounts job hash
+	DatabaseAccountsHash string `json:"databaseAccountsHash,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,33 +17,82 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// NovaAPIWSGI - httpd/mod_wsgi process model of the nova-api
+type NovaAPIWSGI struct {
+	// Number of WSGI daemon processes, which are the API workers. Defaults to the CPU limit
+	// of the container rounded up, or 4 without CPU limit
+	// +kubebuilder:validation:Minimum=1
+	Processes int32 `json:"processes,omitempty"`
+	// Number of threads per WSGI daemon process, defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	Threads int32 `json:"threads,omitempty"`
+	// Seconds httpd waits for I/O on a connection, defaults to 60
+	// +kubebuilder:validation:Minimum=1
+	Timeout int32 `json:"timeout,omitempty"`
+	// Seconds before a request to a WSGI daemon process gets aborted, defaults to the Timeout
+	// +kubebuilder:validation:Minimum=1
+	RequestTimeout int32 `json:"requestTimeout,omitempty"`
+}
+
 // NovaAPISpec defines the desired state of NovaAPI
 type NovaAPISpec struct {
 	// CR name of managing controller object to identify the config maps
 	ManagingCrName string `json:"managingCrName,omitempty"`
 	// Nova Database Hostname String
 	DatabaseHostname string `json:"databaseHostname,omitempty"`
+	// TLS settings of the database connections
+	DatabaseTLS DatabaseTLS `json:"databaseTLS,omitempty"`
+	// DB account of the service, the account named like the database is used if not set
+	DatabaseUser string `json:"databaseUser,omitempty"`
 	// Nova Scheduler Container Image URL
 	ContainerImage string `json:"containerImage,omitempty"`
 	// Nova API Replicas
 	Replicas int32 `json:"replicas"`
+	// Compute resources of the nova-api and init containers
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Priority class of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Node placement of the pods
+	Placement `json:",inline"`
+	// Disruption budget of the pods, created with more than one replica
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+	// Horizontal pod autoscaling of the nova-api
+	Autoscaling Autoscaling `json:"autoscaling,omitempty"`
+	// httpd/mod_wsgi process model
+	WSGI NovaAPIWSGI `json:"wsgi,omitempty"`
+	// HTTP probes against the version document of the API
+	Probes Probes `json:"probes,omitempty"`
 	// Secret containing: NovaPassword, TransportURL
 	NovaSecret string `json:"novaSecret,omitempty"`
 	// Secret containing: PlacementPassword
 	PlacementSecret string `json:"placementSecret,omitempty"`
 	// Secret containing: NeutronPassword
 	NeutronSecret string `json:"neutronSecret,omitempty"`
+	// Secret containing: ApplicationCredentialID, ApplicationCredentialSecret, the nova service
+	// authenticates with the application credential instead of the NovaKeystoneAuthPassword if set
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
 	// Secret containing: cell transport_url
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// Secret containing: notifications transport_url
+	NotificationsTransportURLSecret string `json:"notificationsTransportURLSecret,omitempty"`
+	// Key names of the credentials in the referenced secrets
+	PasswordSelectors PasswordSelector `json:"passwordSelectors,omitempty"`
+	// API policy overrides
+	Policy Policy `json:"policy,omitempty"`
+	// Hash of the created cells, a change triggers a rolling restart to pick up new cell mappings
+	CellMappingsHash string `json:"cellMappingsHash,omitempty"`
 }
 
 // NovaAPIStatus defines the observed state of NovaAPI
 type NovaAPIStatus struct {
 	// hashes of Secrets, CMs
 	Hashes []Hash `json:"hashes,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
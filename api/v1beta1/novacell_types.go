@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,6 +27,13 @@ type NovaCellSpec struct {
 	Cell string `json:"cell,omitempty"`
 	// Nova Database Hostname String
 	DatabaseHostname string `json:"databaseHostname,omitempty"`
+	// TLS settings of the database connections
+	DatabaseTLS DatabaseTLS `json:"databaseTLS,omitempty"`
+	// Secret containing the root password of the DB server, used by the DB accounts job
+	// to create the DB accounts of the services
+	DatabaseAdminSecret string `json:"databaseAdminSecret,omitempty"`
+	// Name of the Memcached instance to cache keystone tokens and API data in
+	MemcachedInstance string `json:"memcachedInstance,omitempty"`
 	// Nova Conductor Container Image URL
 	NovaConductorContainerImage string `json:"novaConductorContainerImage,omitempty"`
 	// Nova Metadata Container Image URL
@@ -38,14 +46,44 @@ type NovaCellSpec struct {
 	NovaMetadataReplicas int32 `json:"novaMetadataReplicas"`
 	// Nova NoVNC Replicas
 	NovaNoVNCProxyReplicas int32 `json:"novaNoVNCProxyReplicas"`
+	// Nova Conductor compute resources
+	NovaConductorResources corev1.ResourceRequirements `json:"novaConductorResources,omitempty"`
+	// Nova Metadata compute resources
+	NovaMetadataResources corev1.ResourceRequirements `json:"novaMetadataResources,omitempty"`
+	// Nova Metadata horizontal pod autoscaling
+	NovaMetadataAutoscaling Autoscaling `json:"novaMetadataAutoscaling,omitempty"`
+	// Nova NoVNC compute resources
+	NovaNoVNCProxyResources corev1.ResourceRequirements `json:"novaNoVNCProxyResources,omitempty"`
+	// Compute resources of the db sync and create cell jobs
+	JobResources corev1.ResourceRequirements `json:"jobResources,omitempty"`
+	// Priority class of the pods of all services and jobs
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Node placement of the pods of all control plane services
+	Placement Placement `json:"placement,omitempty"`
+	// Disruption budget of the pods of all control plane services
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
 	// Secret containing: NovaPassword, TransportURL
 	NovaSecret string `json:"novaSecret,omitempty"`
 	// Secret containing: PlacementPassword
 	PlacementSecret string `json:"placementSecret,omitempty"`
 	// Secret containing: NeutronPassword
 	NeutronSecret string `json:"neutronSecret,omitempty"`
+	// Secret containing: ApplicationCredentialID, ApplicationCredentialSecret, the nova service
+	// authenticates with the application credential instead of the NovaKeystoneAuthPassword if set
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
 	// Secret containing: cell transport_url
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// Notifications emitted by the services
+	Notifications Notifications `json:"notifications,omitempty"`
+	// Key names of the credentials in the referenced secrets
+	PasswordSelectors PasswordSelector `json:"passwordSelectors,omitempty"`
+	// Nova Conductor health check probe thresholds
+	NovaConductorProbes Probes `json:"novaConductorProbes,omitempty"`
+	// API policy overrides of the metadata service
+	Policy Policy `json:"policy,omitempty"`
+	// Number of retries before the db sync and create cell jobs are
+	// considered failed, defaults to 6
+	JobRetryLimit int32 `json:"jobRetryLimit,omitempty"`
 }
 
 // NovaCellStatus defines the observed state of NovaCell
@@ -58,6 +96,12 @@ type NovaCellStatus struct {
 	CreateCellHash string `json:"createCellHash"`
 	// noVNC endpoint
 	NoVNCProxyEndpoint string `json:"noVNCProxyEndpoint"`
+	// NovaSecret used by the DB accounts and services, differs from the spec during a DB password rotation
+	DatabaseSecret string `json:"databaseSecret,omitempty"`
+	// DatabaseAccountsHash DB accounts job hash
+	DatabaseAccountsHash string `json:"databaseAccountsHash,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,8 +33,16 @@ type NovaComputeSpec struct {
 	// Ex. NovaComputeCPUDedicatedSet: [4-12,^8,15] will reserve cores from 4-12
 	// and 15, excluding 8.
 	NovaComputeCPUDedicatedSet string `json:"novaComputeCPUDedicatedSet,omitempty"`
-	// Name of the worker role created for OSP computes
-	RoleName string `json:"roleName"`
+	// Name of the worker role created for OSP computes, shorthand for the node-role.kubernetes.io/<RoleName>
+	// node selector and the dedicated=<RoleName> toleration
+	RoleName string `json:"roleName,omitempty"`
+	// Node selector, node affinity and tolerations of the pods
+	ComputePlacement `json:",inline"`
+	// Compute resources of the nova-compute and init containers
+	// Requests equal to the limits give the pods guaranteed QoS on the compute nodes
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Priority class of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 	// Name of the cell, e.g. cell1
 	Cell string `json:"cell,omitempty"`
 	// Secret containing: NovaPassword, TransportURL
@@ -42,8 +51,48 @@ type NovaComputeSpec struct {
 	PlacementSecret string `json:"placementSecret,omitempty"`
 	// Secret containing: NeutronPassword
 	NeutronSecret string `json:"neutronSecret,omitempty"`
+	// Secret containing: ApplicationCredentialID, ApplicationCredentialSecret, the nova service
+	// authenticates with the application credential instead of the NovaKeystoneAuthPassword if set
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
 	// Secret containing: cell transport_url
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// Notifications emitted by the compute service, should match the Nova notifications
+	Notifications Notifications `json:"notifications,omitempty"`
+	// Key names of the credentials in the referenced secrets
+	PasswordSelectors PasswordSelector `json:"passwordSelectors,omitempty"`
+	// Heartbeat based health check probe thresholds
+	Probes Probes `json:"probes,omitempty"`
+	// Report the state of the nova-compute service of the nodes from the os-services API of the internal
+	// compute endpoint in the catalog, polled every minute as the nova service user, or with the
+	// application credential if set
+	ReportServiceState bool `json:"reportServiceState,omitempty"`
+	// Keystone endpoint the nova service user, or the application credential if set, authenticates against
+	// to report the service state, defaults to http://keystone.openstack.svc:5000/v3
+	KeystoneAuthURL string `json:"keystoneAuthURL,omitempty"`
+}
+
+// NovaComputeNodeStatus - state of nova-compute on a node
+type NovaComputeNodeStatus struct {
+	// Name of the node
+	NodeName string `json:"nodeName"`
+	// Name of the nova-compute pod on the node
+	PodName string `json:"podName,omitempty"`
+	// The nova-compute pod is ready
+	Ready bool `json:"ready"`
+	// The node is ready and schedulable
+	NodeReady bool `json:"nodeReady"`
+	// Revision of the DaemonSet pod template the pod runs with
+	Revision string `json:"revision,omitempty"`
+	// The pod runs with the current Revision of the DaemonSet
+	UpToDate bool `json:"upToDate"`
+	// The nova-compute container is running and passes its heartbeat based readiness probe
+	ContainerReady bool `json:"containerReady"`
+	// State of the nova-compute service of the node reported by the API, up or down, if ReportServiceState is set
+	ServiceState string `json:"serviceState,omitempty"`
+	// Status of the nova-compute service of the node reported by the API, enabled or disabled, if ReportServiceState is set
+	ServiceStatus string `json:"serviceStatus,omitempty"`
+	// Reason the nova-compute service of the node got disabled
+	ServiceDisabledReason string `json:"serviceDisabledReason,omitempty"`
 }
 
 // NovaComputeStatus defines the observed state of NovaCompute
@@ -52,6 +101,12 @@ type NovaComputeStatus struct {
 	Count int32 `json:"count"`
 	// hashes of Secrets, CMs
 	Hashes []Hash `json:"hashes,omitempty"`
+	// Current revision of the DaemonSet pod template
+	CurrentRevision string `json:"currentRevision,omitempty"`
+	// State of nova-compute on the nodes the daemon is deployed to
+	Nodes []NovaComputeNodeStatus `json:"nodes,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
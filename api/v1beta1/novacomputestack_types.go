@@ -0,0 +1,135 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NovaComputeStackSpec defines the desired state of NovaComputeStack
+type NovaComputeStackSpec struct {
+	// Name of the worker role created for OSP computes, shorthand for the node-role.kubernetes.io/<RoleName>
+	// node selector and the dedicated=<RoleName> toleration
+	RoleName string `json:"roleName,omitempty"`
+	// Node selector, node affinity and tolerations of the pods of all daemons
+	ComputePlacement `json:",inline"`
+	// Priority class of the pods of all daemons
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Libvirt Container Image URL of the libvirtd and virtlogd daemons
+	NovaLibvirtImage string `json:"novaLibvirtImage"`
+	// Iscsid Container Image URL
+	IscsidImage string `json:"iscsidImage"`
+	// Nova Compute Container Image URL of the nova-compute and nova-migration-target daemons
+	NovaComputeImage string `json:"novaComputeImage"`
+	// SSHD port of the nova-migration-target
+	SshdPort int32 `json:"sshdPort"`
+	// Libvirtd compute resources
+	LibvirtdResources corev1.ResourceRequirements `json:"libvirtdResources,omitempty"`
+	// Virtlogd compute resources
+	VirtlogdResources corev1.ResourceRequirements `json:"virtlogdResources,omitempty"`
+	// Iscsid compute resources
+	IscsidResources corev1.ResourceRequirements `json:"iscsidResources,omitempty"`
+	// Nova Migration Target compute resources
+	NovaMigrationTargetResources corev1.ResourceRequirements `json:"novaMigrationTargetResources,omitempty"`
+	// Nova Compute compute resources
+	NovaComputeResources corev1.ResourceRequirements `json:"novaComputeResources,omitempty"`
+	// Mask of host CPUs that can be used for ``VCPU`` resources and offloaded
+	// emulator threads. For more information, refer to the documentation.
+	NovaComputeCPUSharedSet string `json:"novaComputeCPUSharedSet,omitempty"`
+	// A list or range of host CPU cores to which processes for pinned instance
+	// CPUs (PCPUs) can be scheduled.
+	NovaComputeCPUDedicatedSet string `json:"novaComputeCPUDedicatedSet,omitempty"`
+	// Nova Compute heartbeat based health check probe thresholds
+	NovaComputeProbes Probes `json:"novaComputeProbes,omitempty"`
+	// Name of the cell, e.g. cell1
+	Cell string `json:"cell,omitempty"`
+	// Secret containing: NovaPassword, TransportURL
+	NovaSecret string `json:"novaSecret,omitempty"`
+	// Secret containing: PlacementPassword
+	PlacementSecret string `json:"placementSecret,omitempty"`
+	// Secret containing: NeutronPassword
+	NeutronSecret string `json:"neutronSecret,omitempty"`
+	// Secret containing: ApplicationCredentialID, ApplicationCredentialSecret, the nova service
+	// authenticates with the application credential instead of the NovaKeystoneAuthPassword if set
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
+	// Secret containing: cell transport_url
+	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// Notifications emitted by the compute service, should match the Nova notifications
+	Notifications Notifications `json:"notifications,omitempty"`
+	// Key names of the credentials in the referenced secrets
+	PasswordSelectors PasswordSelector `json:"passwordSelectors,omitempty"`
+	// Report the state of the nova-compute service of the nodes from the os-services API in the
+	// NovaCompute status
+	ReportServiceState bool `json:"reportServiceState,omitempty"`
+	// Keystone endpoint to authenticate against to report the service state, defaults to
+	// http://keystone.openstack.svc:5000/v3
+	KeystoneAuthURL string `json:"keystoneAuthURL,omitempty"`
+}
+
+// DaemonStatus - rollout state of the DaemonSet of a daemon of the stack
+type DaemonStatus struct {
+	// Name of the CR of the daemon
+	Name string `json:"name,omitempty"`
+	// Number of nodes the daemon should run on
+	Desired int32 `json:"desired"`
+	// Number of nodes the daemon is ready on
+	Ready int32 `json:"ready"`
+}
+
+// NovaComputeStackStatus defines the observed state of NovaComputeStack
+type NovaComputeStackStatus struct {
+	// Libvirtd daemon
+	Libvirtd DaemonStatus `json:"libvirtd,omitempty"`
+	// Virtlogd daemon
+	Virtlogd DaemonStatus `json:"virtlogd,omitempty"`
+	// Iscsid daemon
+	Iscsid DaemonStatus `json:"iscsid,omitempty"`
+	// Nova Migration Target daemon
+	NovaMigrationTarget DaemonStatus `json:"novaMigrationTarget,omitempty"`
+	// Nova Compute daemon
+	NovaCompute DaemonStatus `json:"novaCompute,omitempty"`
+	// Nodes libvirtd and virtlogd got ready on, nova-compute only runs on these nodes
+	HypervisorNodes []string `json:"hypervisorNodes,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// NovaComputeStack is the Schema for the novacomputestacks API
+type NovaComputeStack struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NovaComputeStackSpec   `json:"spec,omitempty"`
+	Status NovaComputeStackStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NovaComputeStackList contains a list of NovaComputeStack
+type NovaComputeStackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NovaComputeStack `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NovaComputeStack{}, &NovaComputeStackList{})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,8 +27,15 @@ type NovaMigrationTargetSpec struct {
 	NovaComputeImage string `json:"novaComputeImage"`
 	// SSHD port
 	SshdPort int32 `json:"sshdPort"`
-	// Name of the worker role created for OSP computes
-	RoleName string `json:"roleName"`
+	// Name of the worker role created for OSP computes, shorthand for the node-role.kubernetes.io/<RoleName>
+	// node selector and the dedicated=<RoleName> toleration
+	RoleName string `json:"roleName,omitempty"`
+	// Node selector, node affinity and tolerations of the pods
+	ComputePlacement `json:",inline"`
+	// Compute resources of the nova-migration-target and init containers
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Priority class of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // NovaMigrationTargetStatus defines the observed state of NovaMigrationTarget
@@ -36,6 +44,8 @@ type NovaMigrationTargetStatus struct {
 	Count int32 `json:"count"`
 	// hashes of Secrets, CMs
 	Hashes []Hash `json:"hashes,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
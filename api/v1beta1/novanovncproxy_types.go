@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,24 +29,45 @@ type NovaNoVNCProxySpec struct {
 	Cell string `json:"cell,omitempty"`
 	// Nova Database Hostname String
 	DatabaseHostname string `json:"databaseHostname,omitempty"`
+	// TLS settings of the database connections
+	DatabaseTLS DatabaseTLS `json:"databaseTLS,omitempty"`
+	// DB account of the service, the account named like the database is used if not set
+	DatabaseUser string `json:"databaseUser,omitempty"`
 	// Nova Conductor Container Image URL
 	ContainerImage string `json:"containerImage,omitempty"`
 	// Nova API Replicas
 	Replicas int32 `json:"replicas"`
+	// Compute resources of the nova-novncproxy and init containers
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Priority class of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Node placement of the pods
+	Placement `json:",inline"`
+	// Disruption budget of the pods, created with more than one replica
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
 	// Secret containing: NovaPassword, TransportURL
 	NovaSecret string `json:"novaSecret,omitempty"`
 	// Secret containing: PlacementPassword
 	PlacementSecret string `json:"placementSecret,omitempty"`
 	// Secret containing: NeutronPassword
 	NeutronSecret string `json:"neutronSecret,omitempty"`
+	// Secret containing: ApplicationCredentialID, ApplicationCredentialSecret, the nova service
+	// authenticates with the application credential instead of the NovaKeystoneAuthPassword if set
+	ApplicationCredentialSecret string `json:"applicationCredentialSecret,omitempty"`
 	// Secret containing: cell transport_url
 	TransportURLSecret string `json:"transportURLSecret,omitempty"`
+	// Secret containing: notifications transport_url
+	NotificationsTransportURLSecret string `json:"notificationsTransportURLSecret,omitempty"`
+	// Key names of the credentials in the referenced secrets
+	PasswordSelectors PasswordSelector `json:"passwordSelectors,omitempty"`
 }
 
 // NovaNoVNCProxyStatus defines the observed state of NovaNoVNCProxy
 type NovaNoVNCProxyStatus struct {
 	// hashes of Secrets, CMs
 	Hashes []Hash `json:"hashes,omitempty"`
+	// Conditions
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
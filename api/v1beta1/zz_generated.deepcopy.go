@@ -21,12 +21,52 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Autoscaling) DeepCopyInto(out *Autoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2beta2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Autoscaling.
+func (in *Autoscaling) DeepCopy() *Autoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(Autoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cell) DeepCopyInto(out *Cell) {
 	*out = *in
+	out.MessagingBus = in.MessagingBus
+	in.NovaConductorResources.DeepCopyInto(&out.NovaConductorResources)
+	in.NovaMetadataResources.DeepCopyInto(&out.NovaMetadataResources)
+	in.NovaNoVNCProxyResources.DeepCopyInto(&out.NovaNoVNCProxyResources)
+	in.NovaMetadataAutoscaling.DeepCopyInto(&out.NovaMetadataAutoscaling)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cell.
@@ -39,6 +79,86 @@ func (in *Cell) DeepCopy() *Cell {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputePlacement) DeepCopyInto(out *ComputePlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(v1.NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputePlacement.
+func (in *ComputePlacement) DeepCopy() *ComputePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonStatus) DeepCopyInto(out *DaemonStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonStatus.
+func (in *DaemonStatus) DeepCopy() *DaemonStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseTLS) DeepCopyInto(out *DatabaseTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseTLS.
+func (in *DatabaseTLS) DeepCopy() *DatabaseTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hash) DeepCopyInto(out *Hash) {
 	*out = *in
@@ -59,7 +179,7 @@ func (in *Iscsid) DeepCopyInto(out *Iscsid) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -116,6 +236,8 @@ func (in *IscsidList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IscsidSpec) DeepCopyInto(out *IscsidSpec) {
 	*out = *in
+	in.ComputePlacement.DeepCopyInto(&out.ComputePlacement)
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IscsidSpec.
@@ -136,6 +258,13 @@ func (in *IscsidStatus) DeepCopyInto(out *IscsidStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IscsidStatus.
@@ -153,7 +282,7 @@ func (in *Libvirtd) DeepCopyInto(out *Libvirtd) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -210,6 +339,8 @@ func (in *LibvirtdList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LibvirtdSpec) DeepCopyInto(out *LibvirtdSpec) {
 	*out = *in
+	in.ComputePlacement.DeepCopyInto(&out.ComputePlacement)
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibvirtdSpec.
@@ -230,6 +361,13 @@ func (in *LibvirtdStatus) DeepCopyInto(out *LibvirtdStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibvirtdStatus.
@@ -242,13 +380,48 @@ func (in *LibvirtdStatus) DeepCopy() *LibvirtdStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessagingBus) DeepCopyInto(out *MessagingBus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessagingBus.
+func (in *MessagingBus) DeepCopy() *MessagingBus {
+	if in == nil {
+		return nil
+	}
+	out := new(MessagingBus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notifications) DeepCopyInto(out *Notifications) {
+	*out = *in
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notifications.
+func (in *Notifications) DeepCopy() *Notifications {
+	if in == nil {
+		return nil
+	}
+	out := new(Notifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nova) DeepCopyInto(out *Nova) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nova.
@@ -274,7 +447,7 @@ func (in *NovaAPI) DeepCopyInto(out *NovaAPI) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -331,6 +504,15 @@ func (in *NovaAPIList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaAPISpec) DeepCopyInto(out *NovaAPISpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	out.WSGI = in.WSGI
+	out.Probes = in.Probes
+	out.PasswordSelectors = in.PasswordSelectors
+	out.Policy = in.Policy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaAPISpec.
@@ -351,6 +533,13 @@ func (in *NovaAPIStatus) DeepCopyInto(out *NovaAPIStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaAPIStatus.
@@ -363,12 +552,27 @@ func (in *NovaAPIStatus) DeepCopy() *NovaAPIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NovaAPIWSGI) DeepCopyInto(out *NovaAPIWSGI) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaAPIWSGI.
+func (in *NovaAPIWSGI) DeepCopy() *NovaAPIWSGI {
+	if in == nil {
+		return nil
+	}
+	out := new(NovaAPIWSGI)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaCell) DeepCopyInto(out *NovaCell) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -425,6 +629,18 @@ func (in *NovaCellList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaCellSpec) DeepCopyInto(out *NovaCellSpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.NovaConductorResources.DeepCopyInto(&out.NovaConductorResources)
+	in.NovaMetadataResources.DeepCopyInto(&out.NovaMetadataResources)
+	in.NovaMetadataAutoscaling.DeepCopyInto(&out.NovaMetadataAutoscaling)
+	in.NovaNoVNCProxyResources.DeepCopyInto(&out.NovaNoVNCProxyResources)
+	in.JobResources.DeepCopyInto(&out.JobResources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	out.PasswordSelectors = in.PasswordSelectors
+	out.NovaConductorProbes = in.NovaConductorProbes
+	out.Policy = in.Policy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaCellSpec.
@@ -445,6 +661,13 @@ func (in *NovaCellStatus) DeepCopyInto(out *NovaCellStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaCellStatus.
@@ -462,7 +685,7 @@ func (in *NovaCompute) DeepCopyInto(out *NovaCompute) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -516,9 +739,29 @@ func (in *NovaComputeList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NovaComputeNodeStatus) DeepCopyInto(out *NovaComputeNodeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeNodeStatus.
+func (in *NovaComputeNodeStatus) DeepCopy() *NovaComputeNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NovaComputeNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaComputeSpec) DeepCopyInto(out *NovaComputeSpec) {
 	*out = *in
+	in.ComputePlacement.DeepCopyInto(&out.ComputePlacement)
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	out.PasswordSelectors = in.PasswordSelectors
+	out.Probes = in.Probes
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeSpec.
@@ -531,6 +774,121 @@ func (in *NovaComputeSpec) DeepCopy() *NovaComputeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NovaComputeStack) DeepCopyInto(out *NovaComputeStack) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeStack.
+func (in *NovaComputeStack) DeepCopy() *NovaComputeStack {
+	if in == nil {
+		return nil
+	}
+	out := new(NovaComputeStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NovaComputeStack) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NovaComputeStackList) DeepCopyInto(out *NovaComputeStackList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NovaComputeStack, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeStackList.
+func (in *NovaComputeStackList) DeepCopy() *NovaComputeStackList {
+	if in == nil {
+		return nil
+	}
+	out := new(NovaComputeStackList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NovaComputeStackList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NovaComputeStackSpec) DeepCopyInto(out *NovaComputeStackSpec) {
+	*out = *in
+	in.ComputePlacement.DeepCopyInto(&out.ComputePlacement)
+	in.LibvirtdResources.DeepCopyInto(&out.LibvirtdResources)
+	in.VirtlogdResources.DeepCopyInto(&out.VirtlogdResources)
+	in.IscsidResources.DeepCopyInto(&out.IscsidResources)
+	in.NovaMigrationTargetResources.DeepCopyInto(&out.NovaMigrationTargetResources)
+	in.NovaComputeResources.DeepCopyInto(&out.NovaComputeResources)
+	out.NovaComputeProbes = in.NovaComputeProbes
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	out.PasswordSelectors = in.PasswordSelectors
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeStackSpec.
+func (in *NovaComputeStackSpec) DeepCopy() *NovaComputeStackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NovaComputeStackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NovaComputeStackStatus) DeepCopyInto(out *NovaComputeStackStatus) {
+	*out = *in
+	out.Libvirtd = in.Libvirtd
+	out.Virtlogd = in.Virtlogd
+	out.Iscsid = in.Iscsid
+	out.NovaMigrationTarget = in.NovaMigrationTarget
+	out.NovaCompute = in.NovaCompute
+	if in.HypervisorNodes != nil {
+		in, out := &in.HypervisorNodes, &out.HypervisorNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeStackStatus.
+func (in *NovaComputeStackStatus) DeepCopy() *NovaComputeStackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NovaComputeStackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaComputeStatus) DeepCopyInto(out *NovaComputeStatus) {
 	*out = *in
@@ -539,6 +897,18 @@ func (in *NovaComputeStatus) DeepCopyInto(out *NovaComputeStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NovaComputeNodeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaComputeStatus.
@@ -556,7 +926,7 @@ func (in *NovaConductor) DeepCopyInto(out *NovaConductor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -613,6 +983,12 @@ func (in *NovaConductorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaConductorSpec) DeepCopyInto(out *NovaConductorSpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	out.PasswordSelectors = in.PasswordSelectors
+	out.Probes = in.Probes
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaConductorSpec.
@@ -633,6 +1009,13 @@ func (in *NovaConductorStatus) DeepCopyInto(out *NovaConductorStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaConductorStatus.
@@ -682,7 +1065,7 @@ func (in *NovaMetadata) DeepCopyInto(out *NovaMetadata) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -739,6 +1122,13 @@ func (in *NovaMetadataList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaMetadataSpec) DeepCopyInto(out *NovaMetadataSpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	out.PasswordSelectors = in.PasswordSelectors
+	out.Policy = in.Policy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaMetadataSpec.
@@ -759,6 +1149,13 @@ func (in *NovaMetadataStatus) DeepCopyInto(out *NovaMetadataStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaMetadataStatus.
@@ -776,7 +1173,7 @@ func (in *NovaMigrationTarget) DeepCopyInto(out *NovaMigrationTarget) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -833,6 +1230,8 @@ func (in *NovaMigrationTargetList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaMigrationTargetSpec) DeepCopyInto(out *NovaMigrationTargetSpec) {
 	*out = *in
+	in.ComputePlacement.DeepCopyInto(&out.ComputePlacement)
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaMigrationTargetSpec.
@@ -853,6 +1252,13 @@ func (in *NovaMigrationTargetStatus) DeepCopyInto(out *NovaMigrationTargetStatus
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaMigrationTargetStatus.
@@ -870,7 +1276,7 @@ func (in *NovaNoVNCProxy) DeepCopyInto(out *NovaNoVNCProxy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -927,6 +1333,11 @@ func (in *NovaNoVNCProxyList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaNoVNCProxySpec) DeepCopyInto(out *NovaNoVNCProxySpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	out.PasswordSelectors = in.PasswordSelectors
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaNoVNCProxySpec.
@@ -947,6 +1358,13 @@ func (in *NovaNoVNCProxyStatus) DeepCopyInto(out *NovaNoVNCProxyStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaNoVNCProxyStatus.
@@ -964,7 +1382,7 @@ func (in *NovaScheduler) DeepCopyInto(out *NovaScheduler) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1021,6 +1439,12 @@ func (in *NovaSchedulerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaSchedulerSpec) DeepCopyInto(out *NovaSchedulerSpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	out.PasswordSelectors = in.PasswordSelectors
+	out.Probes = in.Probes
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaSchedulerSpec.
@@ -1041,6 +1465,13 @@ func (in *NovaSchedulerStatus) DeepCopyInto(out *NovaSchedulerStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaSchedulerStatus.
@@ -1056,10 +1487,28 @@ func (in *NovaSchedulerStatus) DeepCopy() *NovaSchedulerStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaSpec) DeepCopyInto(out *NovaSpec) {
 	*out = *in
+	out.DatabaseTLS = in.DatabaseTLS
+	in.NovaAPIResources.DeepCopyInto(&out.NovaAPIResources)
+	in.NovaSchedulerResources.DeepCopyInto(&out.NovaSchedulerResources)
+	in.NovaConductorResources.DeepCopyInto(&out.NovaConductorResources)
+	in.JobResources.DeepCopyInto(&out.JobResources)
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	out.NovaAPIWSGI = in.NovaAPIWSGI
+	in.NovaAPIAutoscaling.DeepCopyInto(&out.NovaAPIAutoscaling)
+	out.NovaAPIProbes = in.NovaAPIProbes
+	out.NovaSchedulerProbes = in.NovaSchedulerProbes
+	out.NovaConductorProbes = in.NovaConductorProbes
+	out.MessagingBus = in.MessagingBus
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	out.PasswordSelectors = in.PasswordSelectors
+	out.Policy = in.Policy
 	if in.Cells != nil {
 		in, out := &in.Cells, &out.Cells
 		*out = make([]Cell, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1076,6 +1525,13 @@ func (in *NovaSpec) DeepCopy() *NovaSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NovaStatus) DeepCopyInto(out *NovaStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NovaStatus.
@@ -1088,12 +1544,141 @@ func (in *NovaStatus) DeepCopy() *NovaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordSelector) DeepCopyInto(out *PasswordSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordSelector.
+func (in *PasswordSelector) DeepCopy() *PasswordSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Placement) DeepCopyInto(out *Placement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Placement.
+func (in *Placement) DeepCopy() *Placement {
+	if in == nil {
+		return nil
+	}
+	out := new(Placement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudget) DeepCopyInto(out *PodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudget.
+func (in *PodDisruptionBudget) DeepCopy() *PodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Policy) DeepCopyInto(out *Policy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
+func (in *Policy) DeepCopy() *Policy {
+	if in == nil {
+		return nil
+	}
+	out := new(Policy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probes) DeepCopyInto(out *Probes) {
+	*out = *in
+	out.Liveness = in.Liveness
+	out.Readiness = in.Readiness
+	out.Startup = in.Startup
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probes.
+func (in *Probes) DeepCopy() *Probes {
+	if in == nil {
+		return nil
+	}
+	out := new(Probes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Virtlogd) DeepCopyInto(out *Virtlogd) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1150,6 +1735,8 @@ func (in *VirtlogdList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtlogdSpec) DeepCopyInto(out *VirtlogdSpec) {
 	*out = *in
+	in.ComputePlacement.DeepCopyInto(&out.ComputePlacement)
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtlogdSpec.
@@ -1170,6 +1757,13 @@ func (in *VirtlogdStatus) DeepCopyInto(out *VirtlogdStatus) {
 		*out = make([]Hash, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtlogdStatus.
@@ -91,6 +91,16 @@ func (r *IscsidReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	// the daemons have to be restricted to the compute nodes
+	err = common.ValidateComputePlacement(instance.Spec.RoleName, instance.Spec.ComputePlacement)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPlacementCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	// Create/update configmaps from templates
 	cmLabels := common.GetLabels(instance.Name, iscsid.AppLabel)
 	cmLabels["upper-cr"] = instance.Name
@@ -178,13 +188,13 @@ func (r *IscsidReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Iscsid,
 	// tolerations
 	tolerations := []corev1.Toleration{}
 	// add compute worker nodes tolerations
-	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName) {
+	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName, instance.Spec.Tolerations) {
 		tolerations = append(tolerations, toleration)
 	}
 
 	/*
 		// add compute worker nodes tolerations
-		for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName) {
+		for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName, instance.Spec.Tolerations) {
 			daemonSet.Spec.Template.Spec.Tolerations = append(daemonSet.Spec.Template.Spec.Tolerations, toleration)
 		}
 	*/
@@ -218,7 +228,9 @@ func (r *IscsidReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Iscsid,
 
 		daemonSet.Spec.Template.Spec = corev1.PodSpec{
 			ServiceAccountName: serviceAccountName,
-			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName),
+			PriorityClassName:  instance.Spec.PriorityClassName,
+			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName, instance.Spec.NodeSelector),
+			Affinity:           common.GetComputeWorkerAffinity(instance.Spec.NodeAffinity),
 			HostIPC:            true,
 			HostNetwork:        true,
 			DNSPolicy:          "ClusterFirstWithHostNet",
@@ -226,8 +238,9 @@ func (r *IscsidReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Iscsid,
 			Tolerations:        tolerations,
 			InitContainers: []corev1.Container{
 				{
-					Name:  "init",
-					Image: instance.Spec.IscsidImage,
+					Name:      "init",
+					Image:     instance.Spec.IscsidImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &runAsUser,
 						Privileged: &trueVar,
@@ -243,6 +256,7 @@ func (r *IscsidReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Iscsid,
 				{
 					Name:           "iscsid",
 					Image:          instance.Spec.IscsidImage,
+					Resources:      instance.Spec.Resources,
 					ReadinessProbe: readinessProbe.GetProbe(),
 					LivenessProbe:  livenessProbe.GetProbe(),
 					SecurityContext: &corev1.SecurityContext{
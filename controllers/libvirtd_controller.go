@@ -90,6 +90,16 @@ func (r *LibvirtdReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	// the daemons have to be restricted to the compute nodes
+	err = common.ValidateComputePlacement(instance.Spec.RoleName, instance.Spec.ComputePlacement)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPlacementCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	envVars := make(map[string]util.EnvSetter)
 	// check for required secrets
 	secretName := strings.ToLower(novamigrationtarget.AppLabel) + "-ssh-keys"
@@ -214,7 +224,7 @@ func (r *LibvirtdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Libvi
 	// tolerations
 	tolerations := []corev1.Toleration{}
 	// add compute worker nodes tolerations
-	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName) {
+	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName, instance.Spec.Tolerations) {
 		tolerations = append(tolerations, toleration)
 	}
 
@@ -248,7 +258,9 @@ func (r *LibvirtdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Libvi
 
 		daemonSet.Spec.Template.Spec = corev1.PodSpec{
 			ServiceAccountName: serviceAccountName,
-			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName),
+			PriorityClassName:  instance.Spec.PriorityClassName,
+			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName, instance.Spec.NodeSelector),
+			Affinity:           common.GetComputeWorkerAffinity(instance.Spec.NodeAffinity),
 			HostIPC:            true,
 			HostPID:            true,
 			HostNetwork:        true,
@@ -257,8 +269,9 @@ func (r *LibvirtdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Libvi
 			Tolerations:        tolerations,
 			InitContainers: []corev1.Container{
 				{
-					Name:  "init",
-					Image: instance.Spec.NovaLibvirtImage,
+					Name:      "init",
+					Image:     instance.Spec.NovaLibvirtImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &runAsUser,
 						Privileged: &trueVar,
@@ -274,6 +287,7 @@ func (r *LibvirtdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Libvi
 				{
 					Name:           "libvirtd",
 					Image:          instance.Spec.NovaLibvirtImage,
+					Resources:      instance.Spec.Resources,
 					ReadinessProbe: readinessProbe,
 					LivenessProbe:  livenessProbe,
 					Lifecycle: &corev1.Lifecycle{
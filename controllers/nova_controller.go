@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -32,6 +33,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	keystonev1beta1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
 	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
@@ -39,6 +42,7 @@ import (
 	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
 	nova "github.com/openstack-k8s-operators/nova-operator/pkg/nova"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -48,6 +52,8 @@ type NovaReconciler struct {
 	Kclient kubernetes.Interface
 	Log     logr.Logger
 	Scheme  *runtime.Scheme
+	// HTTP client of the API endpoint check, a default client is used if not set
+	HTTPClient common.HTTPClient
 }
 
 // GetClient -
@@ -76,6 +82,14 @@ func (r *NovaReconciler) GetScheme() *runtime.Scheme {
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novacells,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novacells/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;create;update;delete;
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=core,resources=pods;pods/log,verbs=get;list;
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=rabbitmq.com,resources=vhosts;users;permissions,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=memcached.openstack.org,resources=memcacheds,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=database.openstack.org,resources=mariadbdatabases,verbs=get;list;watch;create;update;delete;
 
 // Reconcile - nova
 func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -98,29 +112,102 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	envVars := make(map[string]util.EnvSetter)
 
+	// generate the NovaSecret if requested, an existing secret never gets overwritten
+	if instance.Spec.GenerateNovaSecret {
+		err = r.ensureNovaSecret(instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// transport url secret of the api level services, generated from the messaging bus if set
+	transportURLSecret, err := r.getTransportURLSecret(instance, instance.Name, instance.Spec.TransportURLSecret, instance.Spec.MessagingBus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if transportURLSecret == "" {
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
+
 	// check for required secrets
-	novaSecret, hash, err := common.GetSecret(r.Client, instance.Spec.NovaSecret, instance.Namespace)
+	secretKeys := common.GetSecretRequiredKeys(instance.Spec.PasswordSelectors)
+	novaSecret, hash, err := common.GetSecret(r.Client, instance.Spec.NovaSecret, instance.Namespace, secretKeys["novaSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.NovaSecret] = util.EnvValue(hash)
 
-	_, hash, err = common.GetSecret(r.Client, instance.Spec.PlacementSecret, instance.Namespace)
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.PlacementSecret, instance.Namespace, secretKeys["placementSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.PlacementSecret] = util.EnvValue(hash)
 
-	_, hash, err = common.GetSecret(r.Client, instance.Spec.NeutronSecret, instance.Namespace)
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.NeutronSecret, instance.Namespace, secretKeys["neutronSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.NeutronSecret] = util.EnvValue(hash)
 
+	// root passwords of the DB servers to create the DB accounts of the services with
+	for _, server := range r.getDatabaseServers(instance) {
+		err = common.GetDatabaseAdminSecret(r.Client, server, instance.Namespace, instance.Spec.PasswordSelectors)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+	}
+
+	if instance.Spec.Notifications.TransportURLSecret != "" {
+		_, hash, err = common.GetSecret(r.Client, instance.Spec.Notifications.TransportURLSecret, instance.Namespace, secretKeys["notificationsTransportURLSecret"]...)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+		envVars[instance.Spec.Notifications.TransportURLSecret] = util.EnvValue(hash)
+	}
+
+	var applicationCredentialSecret *corev1.Secret
+	if instance.Spec.ApplicationCredentialSecret != "" {
+		applicationCredentialSecret, hash, err = common.GetSecret(r.Client, instance.Spec.ApplicationCredentialSecret, instance.Namespace, secretKeys["applicationCredentialSecret"]...)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+		envVars[instance.Spec.ApplicationCredentialSecret] = util.EnvValue(hash)
+	}
+
+	// DB CA certificate if DB TLS is enabled
+	hash, err = common.GetDatabaseCASecret(r.Client, instance.Spec.DatabaseTLS, instance.Namespace)
+	if err != nil {
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+	}
+	if hash != "" {
+		envVars[instance.Spec.DatabaseTLS.CASecret] = util.EnvValue(hash)
+	}
+
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// memcached servers to cache keystone tokens and API data in
+	memcachedServers := ""
+	if instance.Spec.MemcachedInstance != "" {
+		memcachedServers, err = common.GetMemcachedServers(r, instance.Spec.MemcachedInstance, instance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if memcachedServers == "" {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		}
+	}
+
 	// Create/update configmaps from templates
 	cmLabels := common.GetLabels(instance.Name, nova.AppLabel)
 	cmLabels["upper-cr"] = instance.Name
 
+	templateParameters := common.GetNotificationsConfigOptions(instance.Spec.Notifications)
+	templateParameters["MemcachedServers"] = memcachedServers
+
 	cms := []common.ConfigMap{
 		// ScriptsConfigMap
 		{
@@ -128,7 +215,7 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			Namespace:      instance.Namespace,
 			CMType:         common.CMTypeScripts,
 			InstanceType:   instance.Kind,
-			AdditionalData: map[string]string{"common.sh": "/common/common.sh"},
+			AdditionalData: map[string]string{"common.sh": "/common/common.sh", "healthcheck.sh": "/common/healthcheck.sh", "service-auth.sh": "/common/service-auth.sh", "db-accounts.sh": "/common/db-accounts.sh"},
 			Labels:         cmLabels,
 		},
 		// ConfigMap
@@ -139,6 +226,7 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			InstanceType:   instance.Kind,
 			AdditionalData: map[string]string{},
 			Labels:         cmLabels,
+			ConfigOptions:  templateParameters,
 		},
 		// CustomConfigMap
 		{
@@ -153,14 +241,21 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, nil
 	}
 
+	// NovaSecret of the DB accounts of the databases, switched once all services rolled out with a new one
+	dbSecret := common.GetDatabaseSecret(instance.Spec.NovaSecret, instance.Status.DatabaseSecret)
+	// the MariaDB objects read the password from the DatabasePassword key
+	dbPasswordSecret, err := common.EnsureDatabasePasswordSecret(r, instance, dbSecret, instance.Spec.PasswordSelectors)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Create nova_api and nova_cell0 DBs
-	dbs := [2]string{"nova_api", "nova_cell0"}
-	for _, dbName := range dbs {
+	for _, dbName := range nova.Databases {
 
 		db := common.Database{
 			DatabaseName:     dbName,
 			DatabaseHostname: instance.Spec.DatabaseHostname,
-			Secret:           instance.Spec.NovaSecret,
+			Secret:           dbPasswordSecret,
 		}
 		databaseObj, err := common.DatabaseObject(r, instance, db)
 		if err != nil {
@@ -189,8 +284,63 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 	}
 
+	// DB accounts of nova-api, nova-scheduler and nova-super-conductor, during a DB password rotation
+	// the services use the accounts of the previous NovaSecret until they rolled out with the new one
+	accounts := r.getDatabaseAccounts(instance, instance.Spec.NovaSecret)
+	if instance.Status.DatabaseSecret != "" && instance.Status.DatabaseSecret != instance.Spec.NovaSecret {
+		accounts = append(accounts, r.getDatabaseAccounts(instance, instance.Status.DatabaseSecret)...)
+	}
+	dbAccountsJob, err := common.DatabaseAccountsJob(instance, r.Scheme, common.DatabaseAccountsJobDetails{
+		Name:              instance.Name,
+		ContainerImage:    instance.Spec.NovaAPIContainerImage,
+		UserPrefix:        common.GetDatabaseUserPrefix(nova.APIDatabase),
+		Servers:           r.getDatabaseServers(instance),
+		Accounts:          accounts,
+		PasswordSelectors: instance.Spec.PasswordSelectors,
+		DatabaseTLS:       instance.Spec.DatabaseTLS,
+		JobRetryLimit:     instance.Spec.JobRetryLimit,
+		PriorityClassName: instance.Spec.PriorityClassName,
+		Resources:         instance.Spec.JobResources,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	dbAccountsHash, err := util.ObjectHash(dbAccountsJob)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error calculating DB accounts hash: %v", err)
+	}
+
+	if instance.Status.DatabaseAccountsHash != dbAccountsHash {
+		requeue, err := common.EnsureJob(r, r.Kclient, dbAccountsJob, dbAccountsHash)
+		r.Log.Info("Running DB accounts job")
+		if err != nil && !common.IsJobFailed(err) {
+			return ctrl.Result{}, err
+		}
+		condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetJobCondition(novav1beta1.DatabaseAccountsCondition, requeue, err))
+		if condErr != nil {
+			return ctrl.Result{}, condErr
+		}
+		if err != nil {
+			// the failed job is kept for debugging until it gets replaced to retry
+			r.Log.Info(fmt.Sprintf("DB accounts job failed: %v", err))
+			return ctrl.Result{RequeueAfter: common.GetJobRetryAfter(err)}, nil
+		} else if requeue {
+			// the job watch triggers the next reconcile once it completed
+			r.Log.Info("Waiting on DB accounts")
+			return ctrl.Result{}, nil
+		}
+
+		// DB accounts created... okay to store the hash to disable the job
+		if err := r.setDatabaseAccountsHash(instance, dbAccountsHash); err != nil {
+			return ctrl.Result{}, err
+		}
+		if _, err := util.DeleteJob(dbAccountsJob, r.Kclient, r.Log); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// run dbsync job
-	job := nova.DbSyncJob(instance, r.Scheme)
+	job := nova.DbSyncJob(instance, r.Scheme, transportURLSecret, dbSecret)
 	dbSyncHash, err := util.ObjectHash(job)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error calculating DB sync hash: %v", err)
@@ -198,13 +348,22 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	requeue := true
 	if instance.Status.DbSyncHash != dbSyncHash {
-		requeue, err = util.EnsureJob(job, r.Client, r.Log)
+		requeue, err = common.EnsureJob(r, r.Kclient, job, dbSyncHash)
 		r.Log.Info("Running DB sync")
-		if err != nil {
+		if err != nil && !common.IsJobFailed(err) {
 			return ctrl.Result{}, err
+		}
+		condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetJobCondition(novav1beta1.DbSyncCondition, requeue, err))
+		if condErr != nil {
+			return ctrl.Result{}, condErr
+		}
+		if err != nil {
+			// the failed job is kept for debugging until it gets replaced to retry
+			r.Log.Info(fmt.Sprintf("DB sync failed: %v", err))
+			return ctrl.Result{RequeueAfter: common.GetJobRetryAfter(err)}, nil
 		} else if requeue {
 			r.Log.Info("Waiting on DB sync")
-			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 		}
 	}
 	// db sync completed... okay to store the hash to disable it
@@ -218,9 +377,19 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	// hash of the created cells, nova-api and nova-scheduler get rolled when a cell got created
+	cellMappingsHash, err := r.getCellMappingsHash(instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// NovaSecret to deploy the services with. During a DB password rotation to a new NovaSecret
+	// the services get it one after another, each once the previous one rolled out with it.
+	serviceSecret := instance.Spec.NovaSecret
+
 	// deploy nova-api
 	// Create or update the nova-api Deployment object
-	op, err := r.apiDeploymentCreateOrUpdate(instance)
+	op, err := r.apiDeploymentCreateOrUpdate(instance, cellMappingsHash, serviceSecret, transportURLSecret)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -228,10 +397,15 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
+	rolledOut, err := common.IsDeploymentRolledOut(r, fmt.Sprintf("%s-api", instance.Name), instance.Namespace, serviceSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, rolledOut)
 
-	// deploy nova-super-conductor
-	// Create or update the nova-super-conductor Deployment object
-	op, err = r.conductorDeploymentCreateOrUpdate(instance)
+	// deploy nova-scheduler
+	// Create or update the nova-scheduler Deployment object
+	op, err = r.schedulerDeploymentCreateOrUpdate(instance, cellMappingsHash, serviceSecret, transportURLSecret)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -239,10 +413,15 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
+	rolledOut, err = common.IsDeploymentRolledOut(r, fmt.Sprintf("%s-scheduler", instance.Name), instance.Namespace, serviceSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, rolledOut)
 
-	// deploy nova-scheduler
+	// deploy nova-super-conductor
 	// Create or update the nova-super-conductor Deployment object
-	op, err = r.schedulerDeploymentCreateOrUpdate(instance)
+	op, err = r.conductorDeploymentCreateOrUpdate(instance, serviceSecret, transportURLSecret)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -250,6 +429,11 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
+	rolledOut, err = common.IsStatefulSetRolledOut(r, fmt.Sprintf("%s-super-conductor", instance.Name), instance.Namespace, serviceSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, rolledOut)
 
 	// nova service
 	selector := make(map[string]string)
@@ -305,7 +489,7 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	_, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, novaKeystoneService, func() error {
 		novaKeystoneService.Spec.Username = "nova"
-		novaKeystoneService.Spec.Password = string(novaSecret.Data["NovaKeystoneAuthPassword"])
+		novaKeystoneService.Spec.Password = string(novaSecret.Data[common.GetPasswordSelectors(instance.Spec.PasswordSelectors).Service])
 		novaKeystoneService.Spec.ServiceType = "compute"
 		novaKeystoneService.Spec.ServiceName = "nova"
 		novaKeystoneService.Spec.ServiceDescription = "nova"
@@ -313,7 +497,7 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		novaKeystoneService.Spec.Region = "regionOne"
 		novaKeystoneService.Spec.AdminURL = fmt.Sprintf("http://%s/v2.1", route.Spec.Host)
 		novaKeystoneService.Spec.PublicURL = fmt.Sprintf("http://%s/v2.1", route.Spec.Host)
-		novaKeystoneService.Spec.InternalURL = fmt.Sprintf("http://%s.openstack.svc:8774/v2.1", instance.Name)
+		novaKeystoneService.Spec.InternalURL = fmt.Sprintf("http://%s.%s.svc:8774/v2.1", instance.Name, instance.Namespace)
 
 		return nil
 	})
@@ -322,12 +506,24 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	r.setAPIEndpoint(instance, novaKeystoneService.Spec.PublicURL)
-
 	// Create/Update cells
 	for _, cell := range instance.Spec.Cells {
+		// cell transport url secret, generated from the messaging bus if set. A cell without
+		// transport url secret and messaging bus uses the Nova messaging bus.
+		messagingBus := cell.MessagingBus
+		if messagingBus.Cluster == "" && cell.TransportURLSecret == "" {
+			messagingBus = instance.Spec.MessagingBus
+		}
+		cellTransportURLSecret, err := r.getTransportURLSecret(instance, fmt.Sprintf("%s-%s", instance.Name, cell.Name), cell.TransportURLSecret, messagingBus)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if cellTransportURLSecret == "" {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		}
+
 		// Create or update the nova-cell Deployment object
-		op, err = r.cellDeploymentCreateOrUpdate(instance, &cell)
+		op, err = r.cellDeploymentCreateOrUpdate(instance, &cell, serviceSecret, cellTransportURLSecret)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -335,6 +531,35 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 			return ctrl.Result{}, nil
 		}
+		// the cell rolled out when all its services use the secret
+		novaCell := &novav1beta1.NovaCell{}
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%s", instance.Name, cell.Name), Namespace: instance.Namespace}, novaCell)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, novaCell.Status.DatabaseSecret == serviceSecret)
+	}
+
+	// DB password rotation in progress
+	if serviceSecret != instance.Spec.NovaSecret {
+		r.Log.Info(fmt.Sprintf("Waiting on services to roll out with NovaSecret %s", instance.Spec.NovaSecret))
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	if err := r.setDatabaseSecret(instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the API endpoint gets reported once the internal endpoint responds
+	err = r.checkAPIEndpoint(instance, novaKeystoneService.Spec.InternalURL, novaKeystoneService.Spec.Password, applicationCredentialSecret)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetAPIEndpointCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(fmt.Sprintf("Waiting on API endpoint %s: %v", novaKeystoneService.Spec.InternalURL, err))
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	if err := r.setAPIEndpoint(instance, novaKeystoneService.Spec.PublicURL); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
@@ -342,7 +567,12 @@ func (r *NovaReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 // SetupWithManager -
 func (r *NovaReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.Nova{}); err != nil {
+		return err
+	}
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.Nova{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&novav1beta1.NovaScheduler{}).
@@ -351,7 +581,25 @@ func (r *NovaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&novav1beta1.NovaCell{}).
 		Owns(&routev1.Route{}).
 		Owns(&corev1.Service{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaList{}),
+			}).
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	// watch the RabbitMQ objects the transport urls get derived from
+	err = common.WatchMessagingBus(mgr, c, r, &novav1beta1.Nova{}, &novav1beta1.NovaList{})
+	if err != nil {
+		return err
+	}
+
+	// watch the Memcached instance for server changes
+	return common.WatchMemcached(mgr, c, r, &novav1beta1.Nova{}, &novav1beta1.NovaList{})
 }
 
 func (r *NovaReconciler) setDbSyncHash(api *novav1beta1.Nova, hashStr string) error {
@@ -376,6 +624,23 @@ func (r *NovaReconciler) setDbSyncStatus(api *novav1beta1.Nova, status string) e
 	return nil
 }
 
+// checkAPIEndpoint - version discovery of the endpoint and, if enabled, an authenticated GET /os-services
+// of the nova service user, or of the application credential the services authenticate with if set
+func (r *NovaReconciler) checkAPIEndpoint(instance *novav1beta1.Nova, endpoint string, password string, applicationCredentialSecret *corev1.Secret) error {
+	client := r.HTTPClient
+	if client == nil {
+		client = common.GetDefaultHTTPClient()
+	}
+
+	var credentials *common.KeystoneCredentials
+	if instance.Spec.VerifyAPIServices {
+		c := common.GetKeystoneCredentials(instance.Spec.KeystoneAuthURL, password, applicationCredentialSecret, instance.Spec.PasswordSelectors)
+		credentials = &c
+	}
+
+	return common.CheckAPIEndpoint(client, endpoint, credentials)
+}
+
 func (r *NovaReconciler) setAPIEndpoint(instance *novav1beta1.Nova, endpoint string) error {
 
 	if endpoint != instance.Status.APIEndpoint {
@@ -388,7 +653,7 @@ func (r *NovaReconciler) setAPIEndpoint(instance *novav1beta1.Nova, endpoint str
 
 }
 
-func (r *NovaReconciler) conductorDeploymentCreateOrUpdate(instance *novav1beta1.Nova) (controllerutil.OperationResult, error) {
+func (r *NovaReconciler) conductorDeploymentCreateOrUpdate(instance *novav1beta1.Nova, novaSecret string, transportURLSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaConductor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-super-conductor", instance.Name),
@@ -398,15 +663,25 @@ func (r *NovaReconciler) conductorDeploymentCreateOrUpdate(instance *novav1beta1
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaConductorSpec{
-			ManagingCrName:     instance.Name,
-			Cell:               "cell0",
-			DatabaseHostname:   instance.Spec.DatabaseHostname,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			Replicas:           instance.Spec.NovaConductorReplicas,
-			ContainerImage:     instance.Spec.NovaConductorContainerImage,
+			ManagingCrName:                  instance.Name,
+			Cell:                            "cell0",
+			DatabaseHostname:                instance.Spec.DatabaseHostname,
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    common.GetDatabaseUser(nova.APIDatabase, nova.ConductorService, novaSecret),
+			NovaSecret:                      novaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			TransportURLSecret:              transportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.Notifications.TransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			Replicas:                        instance.Spec.NovaConductorReplicas,
+			ContainerImage:                  instance.Spec.NovaConductorContainerImage,
+			Resources:                       instance.Spec.NovaConductorResources,
+			PriorityClassName:               instance.Spec.PriorityClassName,
+			Placement:                       instance.Spec.Placement,
+			PodDisruptionBudget:             instance.Spec.PodDisruptionBudget,
+			Probes:                          instance.Spec.NovaConductorProbes,
 		}
 
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
@@ -420,7 +695,7 @@ func (r *NovaReconciler) conductorDeploymentCreateOrUpdate(instance *novav1beta1
 	return op, err
 }
 
-func (r *NovaReconciler) apiDeploymentCreateOrUpdate(instance *novav1beta1.Nova) (controllerutil.OperationResult, error) {
+func (r *NovaReconciler) apiDeploymentCreateOrUpdate(instance *novav1beta1.Nova, cellMappingsHash string, novaSecret string, transportURLSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaAPI{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-api", instance.Name),
@@ -430,14 +705,28 @@ func (r *NovaReconciler) apiDeploymentCreateOrUpdate(instance *novav1beta1.Nova)
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaAPISpec{
-			ManagingCrName:     instance.Name,
-			DatabaseHostname:   instance.Spec.DatabaseHostname,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			Replicas:           instance.Spec.NovaAPIReplicas,
-			ContainerImage:     instance.Spec.NovaAPIContainerImage,
+			ManagingCrName:                  instance.Name,
+			DatabaseHostname:                instance.Spec.DatabaseHostname,
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    common.GetDatabaseUser(nova.APIDatabase, nova.APIService, novaSecret),
+			NovaSecret:                      novaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			TransportURLSecret:              transportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.Notifications.TransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			Replicas:                        instance.Spec.NovaAPIReplicas,
+			ContainerImage:                  instance.Spec.NovaAPIContainerImage,
+			CellMappingsHash:                cellMappingsHash,
+			Policy:                          instance.Spec.Policy,
+			Resources:                       instance.Spec.NovaAPIResources,
+			PriorityClassName:               instance.Spec.PriorityClassName,
+			Placement:                       instance.Spec.Placement,
+			PodDisruptionBudget:             instance.Spec.PodDisruptionBudget,
+			WSGI:                            instance.Spec.NovaAPIWSGI,
+			Probes:                          instance.Spec.NovaAPIProbes,
+			Autoscaling:                     instance.Spec.NovaAPIAutoscaling,
 		}
 
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
@@ -451,7 +740,7 @@ func (r *NovaReconciler) apiDeploymentCreateOrUpdate(instance *novav1beta1.Nova)
 	return op, err
 }
 
-func (r *NovaReconciler) schedulerDeploymentCreateOrUpdate(instance *novav1beta1.Nova) (controllerutil.OperationResult, error) {
+func (r *NovaReconciler) schedulerDeploymentCreateOrUpdate(instance *novav1beta1.Nova, cellMappingsHash string, novaSecret string, transportURLSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaScheduler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-scheduler", instance.Name),
@@ -461,14 +750,25 @@ func (r *NovaReconciler) schedulerDeploymentCreateOrUpdate(instance *novav1beta1
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaSchedulerSpec{
-			ManagingCrName:     instance.Name,
-			DatabaseHostname:   instance.Spec.DatabaseHostname,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			Replicas:           instance.Spec.NovaSchedulerReplicas,
-			ContainerImage:     instance.Spec.NovaSchedulerContainerImage,
+			ManagingCrName:                  instance.Name,
+			DatabaseHostname:                instance.Spec.DatabaseHostname,
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    common.GetDatabaseUser(nova.APIDatabase, nova.SchedulerService, novaSecret),
+			NovaSecret:                      novaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			TransportURLSecret:              transportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.Notifications.TransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			Replicas:                        instance.Spec.NovaSchedulerReplicas,
+			ContainerImage:                  instance.Spec.NovaSchedulerContainerImage,
+			Resources:                       instance.Spec.NovaSchedulerResources,
+			PriorityClassName:               instance.Spec.PriorityClassName,
+			Placement:                       instance.Spec.Placement,
+			PodDisruptionBudget:             instance.Spec.PodDisruptionBudget,
+			Probes:                          instance.Spec.NovaSchedulerProbes,
+			CellMappingsHash:                cellMappingsHash,
 		}
 
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
@@ -482,7 +782,195 @@ func (r *NovaReconciler) schedulerDeploymentCreateOrUpdate(instance *novav1beta1
 	return op, err
 }
 
-func (r *NovaReconciler) cellDeploymentCreateOrUpdate(instance *novav1beta1.Nova, cell *novav1beta1.Cell) (controllerutil.OperationResult, error) {
+// getDatabaseAccounts - DB accounts of the api level services for the NovaSecret, granted the api and cell0
+// databases and the databases of the cells they read through the cell mappings
+func (r *NovaReconciler) getDatabaseAccounts(instance *novav1beta1.Nova, secret string) []common.DatabaseAccount {
+	// databases per DB server
+	databases := map[string][]string{
+		instance.Spec.DatabaseHostname: nova.Databases,
+	}
+	for _, cell := range instance.Spec.Cells {
+		hostname := cell.DatabaseHostname
+		if hostname == "" {
+			hostname = instance.Spec.DatabaseHostname
+		}
+		databases[hostname] = append(databases[hostname], fmt.Sprintf("%s_%s", nova.DatabasePrefix, cell.Name))
+	}
+	hostnames := make([]string, 0, len(databases))
+	for hostname := range databases {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	accounts := []common.DatabaseAccount{}
+	for _, service := range []string{nova.APIService, nova.SchedulerService, nova.ConductorService} {
+		for _, hostname := range hostnames {
+			accounts = append(accounts, common.DatabaseAccount{
+				DatabaseHostname: hostname,
+				UserName:         common.GetDatabaseUser(nova.APIDatabase, service, secret),
+				Databases:        databases[hostname],
+				Secret:           secret,
+			})
+		}
+	}
+
+	return accounts
+}
+
+// getDatabaseServers - DB servers of the api and the cell databases with the secret holding their root password
+func (r *NovaReconciler) getDatabaseServers(instance *novav1beta1.Nova) []common.DatabaseServer {
+	adminSecrets := map[string]string{
+		instance.Spec.DatabaseHostname: instance.Spec.DatabaseAdminSecret,
+	}
+	for _, cell := range instance.Spec.Cells {
+		if cell.DatabaseHostname == "" {
+			continue
+		}
+		if _, ok := adminSecrets[cell.DatabaseHostname]; !ok {
+			adminSecrets[cell.DatabaseHostname] = getCellDatabaseAdminSecret(instance, &cell)
+		}
+	}
+
+	servers := []common.DatabaseServer{}
+	for hostname, adminSecret := range adminSecrets {
+		servers = append(servers, common.DatabaseServer{Hostname: hostname, AdminSecret: adminSecret})
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Hostname < servers[j].Hostname })
+
+	return servers
+}
+
+// getCellDatabaseAdminSecret - secret holding the root password of the cell DB server
+func getCellDatabaseAdminSecret(instance *novav1beta1.Nova, cell *novav1beta1.Cell) string {
+	if cell.DatabaseAdminSecret != "" {
+		return cell.DatabaseAdminSecret
+	}
+	return instance.Spec.DatabaseAdminSecret
+}
+
+// setDatabaseAccountsHash - store the hash of the completed DB accounts job
+func (r *NovaReconciler) setDatabaseAccountsHash(instance *novav1beta1.Nova, hashStr string) error {
+	if hashStr != instance.Status.DatabaseAccountsHash {
+		instance.Status.DatabaseAccountsHash = hashStr
+		if err := r.Client.Status().Update(context.TODO(), instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTransportURLSecret - the transport url secret, or the one generated from the messaging bus if set.
+// Returns an empty string while the messaging bus is not ready.
+func (r *NovaReconciler) getTransportURLSecret(instance *novav1beta1.Nova, name string, transportURLSecret string, messagingBus novav1beta1.MessagingBus) (string, error) {
+	if messagingBus.Cluster == "" {
+		return transportURLSecret, nil
+	}
+
+	return common.EnsureTransportURL(r, instance, common.TransportURL{
+		Name:      name,
+		Cluster:   messagingBus.Cluster,
+		SecretKey: common.GetPasswordSelectors(instance.Spec.PasswordSelectors).TransportURL,
+	})
+}
+
+// setDatabaseSecret - all services use the NovaSecret, switch the accounts of the databases to it and delete
+// the previous NovaSecret if it got generated. The DB accounts job then drops the DB accounts of the previous one.
+func (r *NovaReconciler) setDatabaseSecret(instance *novav1beta1.Nova) error {
+	if instance.Status.DatabaseSecret == instance.Spec.NovaSecret {
+		return nil
+	}
+
+	dbPasswordSecret, err := common.EnsureDatabasePasswordSecret(r, instance, instance.Spec.NovaSecret, instance.Spec.PasswordSelectors)
+	if err != nil {
+		return err
+	}
+	for _, dbName := range nova.Databases {
+		_, err := common.EnsureDatabaseSecret(r, instance, common.Database{
+			DatabaseName:     dbName,
+			DatabaseHostname: instance.Spec.DatabaseHostname,
+			Secret:           dbPasswordSecret,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if instance.Status.DatabaseSecret != "" {
+		err = common.DeleteDatabasePasswordSecret(r, instance, instance.Status.DatabaseSecret, instance.Spec.PasswordSelectors)
+		if err != nil {
+			return err
+		}
+
+		oldSecret := &corev1.Secret{}
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Status.DatabaseSecret, Namespace: instance.Namespace}, oldSecret)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return err
+		}
+		// only delete the secret if it got generated by the operator
+		if err == nil && metav1.IsControlledBy(oldSecret, instance) {
+			r.Log.Info(fmt.Sprintf("DB password rotation completed, deleting previous NovaSecret %s", oldSecret.Name))
+			err = r.Client.Delete(context.TODO(), oldSecret)
+			if err != nil && !k8s_errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	instance.Status.DatabaseSecret = instance.Spec.NovaSecret
+	return r.Client.Status().Update(context.TODO(), instance)
+}
+
+// ensureNovaSecret - create the NovaSecret with random passwords if it does not exist
+func (r *NovaReconciler) ensureNovaSecret(instance *novav1beta1.Nova) error {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.NovaSecret, Namespace: instance.Namespace}, secret)
+	if err == nil || !k8s_errors.IsNotFound(err) {
+		return err
+	}
+
+	secret, err = nova.NovaSecret(instance)
+	if err != nil {
+		return err
+	}
+
+	err = controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	r.Log.Info("Creating NovaSecret with generated passwords", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+	return r.Client.Create(context.TODO(), secret)
+}
+
+// getCellMappingsHash - hash of the create cell hashes of all cells which got created
+func (r *NovaReconciler) getCellMappingsHash(instance *novav1beta1.Nova) (string, error) {
+	hashes := []novav1beta1.Hash{}
+	for _, cell := range instance.Spec.Cells {
+		novaCell := &novav1beta1.NovaCell{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%s", instance.Name, cell.Name), Namespace: instance.Namespace}, novaCell)
+		if err != nil && k8s_errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		if novaCell.Status.CreateCellHash != "" {
+			hashes = append(hashes, novav1beta1.Hash{Name: cell.Name, Hash: novaCell.Status.CreateCellHash})
+		}
+	}
+
+	if len(hashes) == 0 {
+		return "", nil
+	}
+
+	hash, err := util.ObjectHash(hashes)
+	if err != nil {
+		return "", fmt.Errorf("error calculating cell mappings hash: %v", err)
+	}
+	return hash, nil
+}
+
+func (r *NovaReconciler) cellDeploymentCreateOrUpdate(instance *novav1beta1.Nova, cell *novav1beta1.Cell, novaSecret string, transportURLSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaCell{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-%s", instance.Name, cell.Name),
@@ -493,16 +981,33 @@ func (r *NovaReconciler) cellDeploymentCreateOrUpdate(instance *novav1beta1.Nova
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaCellSpec{
 			Cell:                         cell.Name,
+			JobRetryLimit:                instance.Spec.JobRetryLimit,
 			DatabaseHostname:             cell.DatabaseHostname,
-			TransportURLSecret:           cell.TransportURLSecret,
+			DatabaseAdminSecret:          getCellDatabaseAdminSecret(instance, cell),
+			DatabaseTLS:                  instance.Spec.DatabaseTLS,
+			MemcachedInstance:            instance.Spec.MemcachedInstance,
+			Policy:                       instance.Spec.Policy,
+			TransportURLSecret:           transportURLSecret,
+			Notifications:                instance.Spec.Notifications,
+			ApplicationCredentialSecret:  instance.Spec.ApplicationCredentialSecret,
 			NovaConductorContainerImage:  cell.NovaConductorContainerImage,
+			NovaConductorProbes:          instance.Spec.NovaConductorProbes,
 			NovaMetadataContainerImage:   cell.NovaMetadataContainerImage,
 			NovaNoVNCProxyContainerImage: cell.NovaNoVNCProxyContainerImage,
 			NovaConductorReplicas:        cell.NovaConductorReplicas,
 			NovaMetadataReplicas:         cell.NovaMetadataReplicas,
 			NovaNoVNCProxyReplicas:       cell.NovaNoVNCProxyReplicas,
-			NovaSecret:                   instance.Spec.NovaSecret,
+			NovaConductorResources:       cell.NovaConductorResources,
+			NovaMetadataResources:        cell.NovaMetadataResources,
+			NovaMetadataAutoscaling:      cell.NovaMetadataAutoscaling,
+			NovaNoVNCProxyResources:      cell.NovaNoVNCProxyResources,
+			JobResources:                 instance.Spec.JobResources,
+			PriorityClassName:            instance.Spec.PriorityClassName,
+			Placement:                    instance.Spec.Placement,
+			PodDisruptionBudget:          instance.Spec.PodDisruptionBudget,
+			NovaSecret:                   novaSecret,
 			PlacementSecret:              instance.Spec.PlacementSecret,
+			PasswordSelectors:            instance.Spec.PasswordSelectors,
 			NeutronSecret:                instance.Spec.NeutronSecret,
 		}
 
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+)
+
+// newTestNovaReconciler - NovaReconciler with a fake client holding the objects
+func newTestNovaReconciler(objs ...runtime.Object) *NovaReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = novav1beta1.AddToScheme(scheme)
+	return &NovaReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, objs...),
+		Log:    ctrl.Log.WithName("test"),
+		Scheme: scheme,
+	}
+}
+
+func newTestNovaCell(name string, createCellHash string) *novav1beta1.NovaCell {
+	return &novav1beta1.NovaCell{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openstack"},
+		Status:     novav1beta1.NovaCellStatus{CreateCellHash: createCellHash},
+	}
+}
+
+func TestGetCellMappingsHash(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.Nova{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova", Namespace: "openstack"},
+		Spec: novav1beta1.NovaSpec{
+			Cells: []novav1beta1.Cell{{Name: "cell1"}},
+		},
+	}
+
+	// no cell created yet
+	r := newTestNovaReconciler(newTestNovaCell("nova-cell1", ""))
+	hash, err := r.getCellMappingsHash(instance)
+	assert.NoError(err)
+	assert.Empty(hash)
+
+	r = newTestNovaReconciler(newTestNovaCell("nova-cell1", "a"))
+	oneCell, err := r.getCellMappingsHash(instance)
+	assert.NoError(err)
+	assert.NotEmpty(oneCell)
+
+	// a second cell in the spec changes the hash only once it got created
+	instance.Spec.Cells = append(instance.Spec.Cells, novav1beta1.Cell{Name: "cell2"})
+	hash, err = r.getCellMappingsHash(instance)
+	assert.NoError(err)
+	assert.Equal(oneCell, hash)
+
+	r = newTestNovaReconciler(newTestNovaCell("nova-cell1", "a"), newTestNovaCell("nova-cell2", ""))
+	hash, err = r.getCellMappingsHash(instance)
+	assert.NoError(err)
+	assert.Equal(oneCell, hash)
+
+	r = newTestNovaReconciler(newTestNovaCell("nova-cell1", "a"), newTestNovaCell("nova-cell2", "b"))
+	twoCells, err := r.getCellMappingsHash(instance)
+	assert.NoError(err)
+	assert.NotEqual(oneCell, twoCells)
+
+	// a cell which got created again changes the hash as well
+	r = newTestNovaReconciler(newTestNovaCell("nova-cell1", "a"), newTestNovaCell("nova-cell2", "c"))
+	hash, err = r.getCellMappingsHash(instance)
+	assert.NoError(err)
+	assert.NotEqual(twoCells, hash)
+}
+
+func TestEnsureNovaSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.Nova{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova", Namespace: "openstack"},
+		Spec: novav1beta1.NovaSpec{
+			NovaSecret:         "nova-secret",
+			GenerateNovaSecret: true,
+			PasswordSelectors:  novav1beta1.PasswordSelector{Database: "NovaDBPassword"},
+		},
+	}
+	r := newTestNovaReconciler(instance)
+
+	assert.NoError(r.ensureNovaSecret(instance))
+	generated := &corev1.Secret{}
+	assert.NoError(r.Client.Get(context.TODO(), types.NamespacedName{Name: "nova-secret", Namespace: "openstack"}, generated))
+	assert.NotEmpty(generated.Data["NovaDBPassword"])
+	assert.True(metav1.IsControlledBy(generated, instance))
+
+	// the generated secret is never regenerated
+	assert.NoError(r.ensureNovaSecret(instance))
+	found := &corev1.Secret{}
+	assert.NoError(r.Client.Get(context.TODO(), types.NamespacedName{Name: "nova-secret", Namespace: "openstack"}, found))
+	assert.Equal(generated.Data, found.Data)
+
+	// an existing secret is never overwritten, not even with changed selectors
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-secret", Namespace: "openstack"},
+		Data:       map[string][]byte{"DatabasePassword": []byte("foo")},
+	}
+	r = newTestNovaReconciler(instance, existing)
+	instance.Spec.NovaSecret = "user-secret"
+	assert.NoError(r.ensureNovaSecret(instance))
+	found = &corev1.Secret{}
+	assert.NoError(r.Client.Get(context.TODO(), types.NamespacedName{Name: "user-secret", Namespace: "openstack"}, found))
+	assert.Equal(existing.Data, found.Data)
+	assert.Empty(found.OwnerReferences)
+}
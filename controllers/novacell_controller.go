@@ -33,12 +33,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
 	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
 	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
 	novacell "github.com/openstack-k8s-operators/nova-operator/pkg/novacell"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -70,6 +73,12 @@ func (r *NovaCellReconciler) GetScheme() *runtime.Scheme {
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novaconductors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novaconductors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;create;update;delete;
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=core,resources=pods;pods/log,verbs=get;list;
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=memcached.openstack.org,resources=memcacheds,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=database.openstack.org,resources=mariadbdatabases,verbs=get;list;watch;create;update;delete;
 
 // Reconcile - nova cell
 func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -99,28 +108,87 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	envVars := make(map[string]util.EnvSetter)
 
 	// check for required secrets
-	_, hash, err := common.GetSecret(r.Client, instance.Spec.NovaSecret, instance.Namespace)
+	secretKeys := common.GetSecretRequiredKeys(instance.Spec.PasswordSelectors)
+	_, hash, err := common.GetSecret(r.Client, instance.Spec.NovaSecret, instance.Namespace, secretKeys["novaSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.NovaSecret] = util.EnvValue(hash)
 
-	_, hash, err = common.GetSecret(r.Client, instance.Spec.PlacementSecret, instance.Namespace)
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.PlacementSecret, instance.Namespace, secretKeys["placementSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.PlacementSecret] = util.EnvValue(hash)
 
-	_, hash, err = common.GetSecret(r.Client, instance.Spec.NeutronSecret, instance.Namespace)
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.NeutronSecret, instance.Namespace, secretKeys["neutronSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.NeutronSecret] = util.EnvValue(hash)
 
+	// root password of the DB server to create the DB accounts of the services with
+	err = common.GetDatabaseAdminSecret(r.Client, r.getDatabaseServer(instance), instance.Namespace, instance.Spec.PasswordSelectors)
+	if err != nil {
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+	}
+
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.TransportURLSecret, instance.Namespace, secretKeys["transportURLSecret"]...)
+	if err != nil {
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+	}
+	envVars[instance.Spec.TransportURLSecret] = util.EnvValue(hash)
+
+	if instance.Spec.Notifications.TransportURLSecret != "" {
+		_, hash, err = common.GetSecret(r.Client, instance.Spec.Notifications.TransportURLSecret, instance.Namespace, secretKeys["notificationsTransportURLSecret"]...)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+		envVars[instance.Spec.Notifications.TransportURLSecret] = util.EnvValue(hash)
+	}
+
+	if instance.Spec.ApplicationCredentialSecret != "" {
+		_, hash, err = common.GetSecret(r.Client, instance.Spec.ApplicationCredentialSecret, instance.Namespace, secretKeys["applicationCredentialSecret"]...)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+		envVars[instance.Spec.ApplicationCredentialSecret] = util.EnvValue(hash)
+	}
+
+	// DB CA certificate if DB TLS is enabled
+	hash, err = common.GetDatabaseCASecret(r.Client, instance.Spec.DatabaseTLS, instance.Namespace)
+	if err != nil {
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+	}
+	if hash != "" {
+		envVars[instance.Spec.DatabaseTLS.CASecret] = util.EnvValue(hash)
+	}
+
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// memcached servers to cache keystone tokens and API data in
+	memcachedServers := ""
+	if instance.Spec.MemcachedInstance != "" {
+		memcachedServers, err = common.GetMemcachedServers(r, instance.Spec.MemcachedInstance, instance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if memcachedServers == "" {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		}
+	}
+
 	// Create/update configmaps from templates
 	cmLabels := common.GetLabels(instance.Name, novacell.AppLabel)
 	cmLabels["upper-cr"] = instance.Name
 
+	templateParameters := common.GetNotificationsConfigOptions(instance.Spec.Notifications)
+	templateParameters["MemcachedServers"] = memcachedServers
+
 	cms := []common.ConfigMap{
 		// ScriptsConfigMap
 		{
@@ -128,7 +196,7 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			Namespace:      instance.Namespace,
 			CMType:         common.CMTypeScripts,
 			InstanceType:   instance.Kind,
-			AdditionalData: map[string]string{"common.sh": "/common/common.sh"},
+			AdditionalData: map[string]string{"common.sh": "/common/common.sh", "healthcheck.sh": "/common/healthcheck.sh", "service-auth.sh": "/common/service-auth.sh", "db-accounts.sh": "/common/db-accounts.sh"},
 			Labels:         cmLabels,
 		},
 		// ConfigMap
@@ -139,6 +207,7 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			InstanceType:   instance.Kind,
 			AdditionalData: map[string]string{},
 			Labels:         cmLabels,
+			ConfigOptions:  templateParameters,
 		},
 		// CustomConfigMap
 		{
@@ -155,10 +224,17 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	// Create the cell DB
 	dbName := fmt.Sprintf("nova_%s", instance.Spec.Cell)
+	// NovaSecret of the DB account of the database, switched once all services rolled out with a new one
+	dbSecret := common.GetDatabaseSecret(instance.Spec.NovaSecret, instance.Status.DatabaseSecret)
+	// the MariaDB objects read the password from the DatabasePassword key
+	dbPasswordSecret, err := common.EnsureDatabasePasswordSecret(r, instance, dbSecret, instance.Spec.PasswordSelectors)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	db := common.Database{
 		DatabaseName:     dbName,
 		DatabaseHostname: instance.Spec.DatabaseHostname,
-		Secret:           instance.Spec.NovaSecret,
+		Secret:           dbPasswordSecret,
 	}
 	databaseObj, err := common.DatabaseObject(r, instance, db)
 	if err != nil {
@@ -187,8 +263,63 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 	}
 
+	// DB accounts of the cell nova-conductor, nova-metadata and nova-novncproxy, during a DB password rotation
+	// the services use the accounts of the previous NovaSecret until they rolled out with the new one
+	accounts := r.getDatabaseAccounts(instance, instance.Spec.NovaSecret)
+	if instance.Status.DatabaseSecret != "" && instance.Status.DatabaseSecret != instance.Spec.NovaSecret {
+		accounts = append(accounts, r.getDatabaseAccounts(instance, instance.Status.DatabaseSecret)...)
+	}
+	dbAccountsJob, err := common.DatabaseAccountsJob(instance, r.Scheme, common.DatabaseAccountsJobDetails{
+		Name:              instance.Name,
+		ContainerImage:    instance.Spec.NovaConductorContainerImage,
+		UserPrefix:        common.GetDatabaseUserPrefix(instance.Spec.Cell),
+		Servers:           []common.DatabaseServer{r.getDatabaseServer(instance)},
+		Accounts:          accounts,
+		PasswordSelectors: instance.Spec.PasswordSelectors,
+		DatabaseTLS:       instance.Spec.DatabaseTLS,
+		JobRetryLimit:     instance.Spec.JobRetryLimit,
+		PriorityClassName: instance.Spec.PriorityClassName,
+		Resources:         instance.Spec.JobResources,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	dbAccountsHash, err := util.ObjectHash(dbAccountsJob)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error calculating DB accounts hash: %v", err)
+	}
+
+	if instance.Status.DatabaseAccountsHash != dbAccountsHash {
+		requeue, err := common.EnsureJob(r, r.Kclient, dbAccountsJob, dbAccountsHash)
+		r.Log.Info("Running DB accounts job")
+		if err != nil && !common.IsJobFailed(err) {
+			return ctrl.Result{}, err
+		}
+		condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetJobCondition(novav1beta1.DatabaseAccountsCondition, requeue, err))
+		if condErr != nil {
+			return ctrl.Result{}, condErr
+		}
+		if err != nil {
+			// the failed job is kept for debugging until it gets replaced to retry
+			r.Log.Info(fmt.Sprintf("DB accounts job failed: %v", err))
+			return ctrl.Result{RequeueAfter: common.GetJobRetryAfter(err)}, nil
+		} else if requeue {
+			// the job watch triggers the next reconcile once it completed
+			r.Log.Info("Waiting on DB accounts")
+			return ctrl.Result{}, nil
+		}
+
+		// DB accounts created... okay to store the hash to disable the job
+		if err := r.setDatabaseAccountsHash(instance, dbAccountsHash); err != nil {
+			return ctrl.Result{}, err
+		}
+		if _, err := util.DeleteJob(dbAccountsJob, r.Kclient, r.Log); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// run dbsync job
-	job := novacell.DbSyncJob(instance, r.Scheme)
+	job := novacell.DbSyncJob(instance, r.Scheme, dbSecret)
 	dbSyncHash, err := util.ObjectHash(job)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error calculating DB sync hash: %v", err)
@@ -196,13 +327,22 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	requeue := true
 	if instance.Status.DbSyncHash != dbSyncHash {
-		requeue, err = util.EnsureJob(job, r.Client, r.Log)
+		requeue, err = common.EnsureJob(r, r.Kclient, job, dbSyncHash)
 		r.Log.Info("Running DB sync")
-		if err != nil {
+		if err != nil && !common.IsJobFailed(err) {
 			return ctrl.Result{}, err
+		}
+		condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetJobCondition(novav1beta1.DbSyncCondition, requeue, err))
+		if condErr != nil {
+			return ctrl.Result{}, condErr
+		}
+		if err != nil {
+			// the failed job is kept for debugging until it gets replaced to retry
+			r.Log.Info(fmt.Sprintf("DB sync failed: %v", err))
+			return ctrl.Result{RequeueAfter: common.GetJobRetryAfter(err)}, nil
 		} else if requeue {
 			r.Log.Info("Waiting on DB sync")
-			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 		}
 	}
 	// db sync completed... okay to store the hash to disable it
@@ -216,8 +356,12 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	// NovaSecret to deploy the services with. During a DB password rotation to a new NovaSecret
+	// the services get it one after another, each once the previous one rolled out with it.
+	serviceSecret := instance.Spec.NovaSecret
+
 	// Create or update the nova-conductor Deployment object
-	op, err := r.conductorDeploymentCreateOrUpdate(instance)
+	op, err := r.conductorDeploymentCreateOrUpdate(instance, serviceSecret)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -225,9 +369,14 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
+	rolledOut, err := common.IsStatefulSetRolledOut(r, fmt.Sprintf("%s-conductor", instance.Name), instance.Namespace, serviceSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, rolledOut)
 
 	// Create or update the nova-matadata Deployment object
-	op, err = r.metadataDeploymentCreateOrUpdate(instance)
+	op, err = r.metadataDeploymentCreateOrUpdate(instance, serviceSecret)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -235,10 +384,15 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
+	rolledOut, err = common.IsDeploymentRolledOut(r, fmt.Sprintf("%s-metadata", instance.Name), instance.Namespace, serviceSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, rolledOut)
 
 	// deploy cell nova-novncproxy
 	// Create or update the nova-novncproxy Deployment object
-	op, err = r.novncproxyDeploymentCreateOrUpdate(instance)
+	op, err = r.novncproxyDeploymentCreateOrUpdate(instance, serviceSecret)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -246,6 +400,22 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
+	rolledOut, err = common.IsDeploymentRolledOut(r, fmt.Sprintf("%s-novncproxy", instance.Name), instance.Namespace, serviceSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceSecret = common.GetRolloutSecret(serviceSecret, instance.Status.DatabaseSecret, rolledOut)
+
+	// DB password rotation in progress
+	if serviceSecret != instance.Spec.NovaSecret {
+		r.Log.Info(fmt.Sprintf("Waiting on services to roll out with NovaSecret %s", instance.Spec.NovaSecret))
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	// all services use the NovaSecret
+	if err := r.setDatabaseSecret(instance, serviceSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// nova noVNC service
 	selector := make(map[string]string)
 	selector["cr"] = fmt.Sprintf("%s-novncproxy", instance.Name)
@@ -298,7 +468,7 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	r.setNoVNCProxyEndpoint(instance, noVncEndpoint)
 
 	// create cell
-	job = novacell.CreateCellJob(instance, r.Scheme)
+	job = novacell.CreateCellJob(instance, r.Scheme, dbSecret)
 	createCellHash, err := util.ObjectHash(job)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error calculating sync hash: %v", err)
@@ -306,25 +476,22 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	requeue = true
 	if instance.Status.CreateCellHash != createCellHash {
-		requeue, err = util.EnsureJob(job, r.Client, r.Log)
+		requeue, err = common.EnsureJob(r, r.Kclient, job, createCellHash)
 		r.Log.Info("Running create Cell")
-		if err != nil {
+		if err != nil && !common.IsJobFailed(err) {
 			return ctrl.Result{}, err
+		}
+		condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetJobCondition(novav1beta1.CreateCellCondition, requeue, err))
+		if condErr != nil {
+			return ctrl.Result{}, condErr
+		}
+		if err != nil {
+			// the failed job is kept for debugging until it gets replaced to retry
+			r.Log.Info(fmt.Sprintf("Create Cell failed: %v", err))
+			return ctrl.Result{RequeueAfter: common.GetJobRetryAfter(err)}, nil
 		} else if requeue {
 			r.Log.Info("Waiting on create Cell")
-			return ctrl.Result{RequeueAfter: time.Second * 5}, err
-		}
-
-		// restart nova-api and nova-scheduler to make aware of new cell only when a new cell got created
-		labels := [2]string{"nova-api", "nova-scheduler"}
-		for _, label := range labels {
-			labelSelector := map[string]string{
-				"app": label,
-			}
-			err = common.DeleteAllNamespacedPodsWithLabel(r.Kclient, r.Log, labelSelector, instance.Namespace)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 		}
 	}
 	// create cell completed... okay to store the hash to disable it
@@ -343,7 +510,12 @@ func (r *NovaCellReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 // SetupWithManager -
 func (r *NovaCellReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.NovaCell{}); err != nil {
+		return err
+	}
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.NovaCell{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&novav1beta1.NovaConductor{}).
@@ -351,7 +523,19 @@ func (r *NovaCellReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&novav1beta1.NovaMetadata{}).
 		Owns(&routev1.Route{}).
 		Owns(&corev1.Service{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaCellList{}),
+			}).
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	// watch the Memcached instance for server changes
+	return common.WatchMemcached(mgr, c, r, &novav1beta1.NovaCell{}, &novav1beta1.NovaCellList{})
 }
 
 func (r *NovaCellReconciler) setDbSyncHash(api *novav1beta1.NovaCell, hashStr string) error {
@@ -376,6 +560,75 @@ func (r *NovaCellReconciler) setCreateCellHash(api *novav1beta1.NovaCell, hashSt
 	return nil
 }
 
+// setDatabaseSecret - all services use the secret, switch the account of the database to it. The DB accounts
+// job then drops the DB accounts of the previous one.
+func (r *NovaCellReconciler) setDatabaseSecret(instance *novav1beta1.NovaCell, secret string) error {
+
+	if secret != instance.Status.DatabaseSecret {
+		dbPasswordSecret, err := common.EnsureDatabasePasswordSecret(r, instance, secret, instance.Spec.PasswordSelectors)
+		if err != nil {
+			return err
+		}
+		_, err = common.EnsureDatabaseSecret(r, instance, common.Database{
+			DatabaseName:     fmt.Sprintf("%s_%s", novacell.DatabasePrefix, instance.Spec.Cell),
+			DatabaseHostname: instance.Spec.DatabaseHostname,
+			Secret:           dbPasswordSecret,
+		})
+		if err != nil {
+			return err
+		}
+		if instance.Status.DatabaseSecret != "" {
+			err = common.DeleteDatabasePasswordSecret(r, instance, instance.Status.DatabaseSecret, instance.Spec.PasswordSelectors)
+			if err != nil {
+				return err
+			}
+		}
+
+		instance.Status.DatabaseSecret = secret
+		if err := r.Client.Status().Update(context.TODO(), instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDatabaseAccounts - DB accounts of the cell services for the NovaSecret, granted the api and the cell database
+func (r *NovaCellReconciler) getDatabaseAccounts(instance *novav1beta1.NovaCell, secret string) []common.DatabaseAccount {
+	accounts := []common.DatabaseAccount{}
+	for _, service := range []string{novacell.ConductorService, novacell.MetadataService, novacell.NoVNCProxyService} {
+		accounts = append(accounts, common.DatabaseAccount{
+			DatabaseHostname: instance.Spec.DatabaseHostname,
+			UserName:         common.GetDatabaseUser(instance.Spec.Cell, service, secret),
+			Databases: []string{
+				fmt.Sprintf("%s_%s", novacell.DatabasePrefix, novacell.APIDatabase),
+				fmt.Sprintf("%s_%s", novacell.DatabasePrefix, instance.Spec.Cell),
+			},
+			Secret: secret,
+		})
+	}
+
+	return accounts
+}
+
+// getDatabaseServer - DB server of the cell database with the secret holding its root password
+func (r *NovaCellReconciler) getDatabaseServer(instance *novav1beta1.NovaCell) common.DatabaseServer {
+	return common.DatabaseServer{
+		Hostname:    instance.Spec.DatabaseHostname,
+		AdminSecret: instance.Spec.DatabaseAdminSecret,
+	}
+}
+
+// setDatabaseAccountsHash - store the hash of the completed DB accounts job
+func (r *NovaCellReconciler) setDatabaseAccountsHash(instance *novav1beta1.NovaCell, hashStr string) error {
+	if hashStr != instance.Status.DatabaseAccountsHash {
+		instance.Status.DatabaseAccountsHash = hashStr
+		if err := r.Client.Status().Update(context.TODO(), instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *NovaCellReconciler) setNoVNCProxyEndpoint(instance *novav1beta1.NovaCell, endpoint string) error {
 
 	if endpoint != instance.Status.NoVNCProxyEndpoint {
@@ -388,7 +641,7 @@ func (r *NovaCellReconciler) setNoVNCProxyEndpoint(instance *novav1beta1.NovaCel
 
 }
 
-func (r *NovaCellReconciler) conductorDeploymentCreateOrUpdate(instance *novav1beta1.NovaCell) (controllerutil.OperationResult, error) {
+func (r *NovaCellReconciler) conductorDeploymentCreateOrUpdate(instance *novav1beta1.NovaCell, novaSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaConductor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-conductor", instance.Name),
@@ -398,15 +651,25 @@ func (r *NovaCellReconciler) conductorDeploymentCreateOrUpdate(instance *novav1b
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaConductorSpec{
-			ManagingCrName:     instance.Name,
-			Cell:               instance.Spec.Cell,
-			DatabaseHostname:   instance.Spec.DatabaseHostname,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			Replicas:           instance.Spec.NovaConductorReplicas,
-			ContainerImage:     instance.Spec.NovaConductorContainerImage,
+			ManagingCrName:                  instance.Name,
+			Cell:                            instance.Spec.Cell,
+			DatabaseHostname:                instance.Spec.DatabaseHostname,
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    common.GetDatabaseUser(instance.Spec.Cell, novacell.ConductorService, novaSecret),
+			NovaSecret:                      novaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.Notifications.TransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			Replicas:                        instance.Spec.NovaConductorReplicas,
+			ContainerImage:                  instance.Spec.NovaConductorContainerImage,
+			Resources:                       instance.Spec.NovaConductorResources,
+			PriorityClassName:               instance.Spec.PriorityClassName,
+			Placement:                       instance.Spec.Placement,
+			PodDisruptionBudget:             instance.Spec.PodDisruptionBudget,
+			Probes:                          instance.Spec.NovaConductorProbes,
 		}
 
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
@@ -420,7 +683,7 @@ func (r *NovaCellReconciler) conductorDeploymentCreateOrUpdate(instance *novav1b
 	return op, err
 }
 
-func (r *NovaCellReconciler) metadataDeploymentCreateOrUpdate(instance *novav1beta1.NovaCell) (controllerutil.OperationResult, error) {
+func (r *NovaCellReconciler) metadataDeploymentCreateOrUpdate(instance *novav1beta1.NovaCell, novaSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaMetadata{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-metadata", instance.Name),
@@ -430,15 +693,26 @@ func (r *NovaCellReconciler) metadataDeploymentCreateOrUpdate(instance *novav1be
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaMetadataSpec{
-			ManagingCrName:     instance.Name,
-			Cell:               instance.Spec.Cell,
-			DatabaseHostname:   instance.Spec.DatabaseHostname,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			Replicas:           instance.Spec.NovaMetadataReplicas,
-			ContainerImage:     instance.Spec.NovaMetadataContainerImage,
+			ManagingCrName:                  instance.Name,
+			Cell:                            instance.Spec.Cell,
+			DatabaseHostname:                instance.Spec.DatabaseHostname,
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    common.GetDatabaseUser(instance.Spec.Cell, novacell.MetadataService, novaSecret),
+			NovaSecret:                      novaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.Notifications.TransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			Replicas:                        instance.Spec.NovaMetadataReplicas,
+			ContainerImage:                  instance.Spec.NovaMetadataContainerImage,
+			Resources:                       instance.Spec.NovaMetadataResources,
+			Autoscaling:                     instance.Spec.NovaMetadataAutoscaling,
+			PriorityClassName:               instance.Spec.PriorityClassName,
+			Placement:                       instance.Spec.Placement,
+			PodDisruptionBudget:             instance.Spec.PodDisruptionBudget,
+			Policy:                          instance.Spec.Policy,
 		}
 
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
@@ -452,7 +726,7 @@ func (r *NovaCellReconciler) metadataDeploymentCreateOrUpdate(instance *novav1be
 	return op, err
 }
 
-func (r *NovaCellReconciler) novncproxyDeploymentCreateOrUpdate(instance *novav1beta1.NovaCell) (controllerutil.OperationResult, error) {
+func (r *NovaCellReconciler) novncproxyDeploymentCreateOrUpdate(instance *novav1beta1.NovaCell, novaSecret string) (controllerutil.OperationResult, error) {
 	deployment := &novav1beta1.NovaNoVNCProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-novncproxy", instance.Name),
@@ -462,15 +736,24 @@ func (r *NovaCellReconciler) novncproxyDeploymentCreateOrUpdate(instance *novav1
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 		deployment.Spec = novav1beta1.NovaNoVNCProxySpec{
-			ManagingCrName:     instance.Name,
-			Cell:               instance.Spec.Cell,
-			DatabaseHostname:   instance.Spec.DatabaseHostname,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			Replicas:           instance.Spec.NovaNoVNCProxyReplicas,
-			ContainerImage:     instance.Spec.NovaNoVNCProxyContainerImage,
+			ManagingCrName:                  instance.Name,
+			Cell:                            instance.Spec.Cell,
+			DatabaseHostname:                instance.Spec.DatabaseHostname,
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    common.GetDatabaseUser(instance.Spec.Cell, novacell.NoVNCProxyService, novaSecret),
+			NovaSecret:                      novaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.Notifications.TransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			Replicas:                        instance.Spec.NovaNoVNCProxyReplicas,
+			ContainerImage:                  instance.Spec.NovaNoVNCProxyContainerImage,
+			Resources:                       instance.Spec.NovaNoVNCProxyResources,
+			PriorityClassName:               instance.Spec.PriorityClassName,
+			Placement:                       instance.Spec.Placement,
+			PodDisruptionBudget:             instance.Spec.PodDisruptionBudget,
 		}
 
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
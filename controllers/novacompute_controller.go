@@ -19,16 +19,21 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
 	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
@@ -48,6 +53,8 @@ type NovaComputeReconciler struct {
 	Kclient kubernetes.Interface
 	Log     logr.Logger
 	Scheme  *runtime.Scheme
+	// HTTPClient of the os-services requests, the default client if nil
+	HTTPClient common.HTTPClient
 }
 
 // GetClient -
@@ -67,8 +74,10 @@ func (r *NovaComputeReconciler) GetScheme() *runtime.Scheme {
 
 // +kubebuilder:rbac:groups=core,namespace=openstack,resources=pods;services;services/finalizers;endpoints;persistentvolumeclaims;events;configmaps;secrets,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=apps,namespace=openstack,resources=daemonsets,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=apps,namespace=openstack,resources=controllerrevisions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=novacomputes,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=novacomputes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=deployments/finalizers,verbs=update
 // +kubebuilder:rbac:groups=security.openshift.io,namespace=openstack,resources=securitycontextconstraints,resourceNames=privileged,verbs=use
 
@@ -91,27 +100,61 @@ func (r *NovaComputeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, err
 	}
 
+	// the daemons have to be restricted to the compute nodes
+	err = common.ValidateComputePlacement(instance.Spec.RoleName, instance.Spec.ComputePlacement)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPlacementCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	envVars := make(map[string]util.EnvSetter)
 
 	// check for required secrets
-	_, hash, err := common.GetSecret(r.Client, instance.Spec.NovaSecret, instance.Namespace)
+	secretKeys := common.GetSecretRequiredKeys(instance.Spec.PasswordSelectors)
+	novaSecret, hash, err := common.GetSecret(r.Client, instance.Spec.NovaSecret, instance.Namespace, common.GetPasswordSelectors(instance.Spec.PasswordSelectors).Service)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.NovaSecret] = util.EnvValue(hash)
 
-	_, hash, err = common.GetSecret(r.Client, instance.Spec.PlacementSecret, instance.Namespace)
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.PlacementSecret, instance.Namespace, secretKeys["placementSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.PlacementSecret] = util.EnvValue(hash)
 
-	_, hash, err = common.GetSecret(r.Client, instance.Spec.NeutronSecret, instance.Namespace)
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.NeutronSecret, instance.Namespace, secretKeys["neutronSecret"]...)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	envVars[instance.Spec.NeutronSecret] = util.EnvValue(hash)
 
+	_, hash, err = common.GetSecret(r.Client, instance.Spec.TransportURLSecret, instance.Namespace, secretKeys["transportURLSecret"]...)
+	if err != nil {
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+	}
+	envVars[instance.Spec.TransportURLSecret] = util.EnvValue(hash)
+
+	if instance.Spec.Notifications.TransportURLSecret != "" {
+		_, hash, err = common.GetSecret(r.Client, instance.Spec.Notifications.TransportURLSecret, instance.Namespace, secretKeys["notificationsTransportURLSecret"]...)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+		envVars[instance.Spec.Notifications.TransportURLSecret] = util.EnvValue(hash)
+	}
+
+	var applicationCredentialSecret *corev1.Secret
+	if instance.Spec.ApplicationCredentialSecret != "" {
+		applicationCredentialSecret, hash, err = common.GetSecret(r.Client, instance.Spec.ApplicationCredentialSecret, instance.Namespace, secretKeys["applicationCredentialSecret"]...)
+		if err != nil {
+			return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
+		}
+		envVars[instance.Spec.ApplicationCredentialSecret] = util.EnvValue(hash)
+	}
+
 	secretName := strings.ToLower(novamigrationtarget.AppLabel) + "-ssh-keys"
 	_, hash, err = common.GetSecret(r.Client, secretName, instance.Namespace)
 	if err != nil {
@@ -119,11 +162,17 @@ func (r *NovaComputeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	}
 	envVars[secretName] = util.EnvValue(hash)
 
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Create/update configmaps from templates
 	cmLabels := common.GetLabels(instance.Name, novacompute.AppLabel)
 	cmLabels["upper-cr"] = instance.Name
 
-	templateParameters := make(map[string]string)
+	templateParameters := common.GetNotificationsConfigOptions(instance.Spec.Notifications)
 	templateParameters["NovaComputeCPUDedicatedSet"] = instance.Spec.NovaComputeCPUDedicatedSet
 	templateParameters["NovaComputeCPUSharedSet"] = instance.Spec.NovaComputeCPUSharedSet
 
@@ -134,7 +183,7 @@ func (r *NovaComputeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 			Namespace:      instance.Namespace,
 			CMType:         common.CMTypeScripts,
 			InstanceType:   instance.Kind,
-			AdditionalData: map[string]string{"common.sh": "/common/common.sh"},
+			AdditionalData: map[string]string{"common.sh": "/common/common.sh", "healthcheck.sh": "/common/healthcheck.sh", "service-auth.sh": "/common/service-auth.sh"},
 			Labels:         cmLabels,
 		},
 		// ConfigMap
@@ -171,16 +220,93 @@ func (r *NovaComputeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
+	// the state of the services in the API is unknown if it can not be retrieved
+	services := []common.NovaService{}
+	if instance.Spec.ReportServiceState {
+		services, err = r.getComputeServices(instance, novaSecret, applicationCredentialSecret)
+		if err != nil {
+			r.Log.Info(fmt.Sprintf("Unable to get the nova-compute services of %s: %v", instance.Name, err))
+		}
+	}
+
+	// update the per node inventory in the status
+	err = r.updateNodeStatuses(instance, services)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the service state changes without an event to watch
+	if instance.Spec.ReportServiceState {
+		return ctrl.Result{RequeueAfter: novacompute.ServiceStatePollInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager -
 func (r *NovaComputeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.NovaCompute{}); err != nil {
+		return err
+	}
+
+	// watch the nova-compute pods of the DaemonSet
+	podFn := handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+		labels := o.Meta.GetLabels()
+		if labels["owner"] != "nova-operator" || labels["app"] != novacompute.AppLabel {
+			return []reconcile.Request{}
+		}
+
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: labels["cr"], Namespace: o.Meta.GetNamespace()}},
+		}
+	})
+
+	// watch the nodes for changes of the node readiness reported in the status
+	nodeFn := handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+		result := []reconcile.Request{}
+
+		node, ok := o.Object.(*corev1.Node)
+		if !ok {
+			return result
+		}
+
+		computes := &novav1beta1.NovaComputeList{}
+		if err := r.Client.List(context.TODO(), computes); err != nil {
+			r.Log.Error(err, "Unable to retrieve NovaCompute CRs")
+			return result
+		}
+
+		for _, compute := range computes.Items {
+			for _, nodeStatus := range compute.Status.Nodes {
+				if nodeStatus.NodeName == node.Name && nodeStatus.NodeReady != novacompute.IsNodeReady(node) {
+					result = append(result, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: compute.Name, Namespace: compute.Namespace},
+					})
+				}
+			}
+		}
+		return result
+	})
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.NovaCompute{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
 		Owns(&appsv1.DaemonSet{}).
+		Watches(&source.Kind{Type: &corev1.Pod{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: podFn,
+			}).
+		Watches(&source.Kind{Type: &corev1.Node{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: nodeFn,
+			}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaComputeList{}),
+			}).
 		Complete(r)
 }
 
@@ -192,10 +318,6 @@ func (r *NovaComputeReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.No
 	envVars["KOLLA_CONFIG_FILE"] = util.EnvValue(novacompute.KollaConfig)
 	envVars["KOLLA_CONFIG_STRATEGY"] = util.EnvValue("COPY_ALWAYS")
 
-	// get readinessProbes
-	readinessProbe := util.Probe{ProbeType: "readiness"}
-	livenessProbe := util.Probe{ProbeType: "liveness"}
-
 	// get volumes
 	initVolumeMounts := common.GetInitVolumeMounts()
 	// add novamigrationtarget init specific VolumeMounts
@@ -216,7 +338,7 @@ func (r *NovaComputeReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.No
 	// tolerations
 	tolerations := []corev1.Toleration{}
 	// add compute worker nodes tolerations
-	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName) {
+	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName, instance.Spec.Tolerations) {
 		tolerations = append(tolerations, toleration)
 	}
 
@@ -255,7 +377,9 @@ func (r *NovaComputeReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.No
 
 		daemonSet.Spec.Template.Spec = corev1.PodSpec{
 			ServiceAccountName: serviceAccountName,
-			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName),
+			PriorityClassName:  instance.Spec.PriorityClassName,
+			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName, instance.Spec.NodeSelector),
+			Affinity:           common.GetComputeWorkerAffinity(instance.Spec.NodeAffinity),
 			HostIPC:            true,
 			HostNetwork:        true,
 			DNSPolicy:          "ClusterFirstWithHostNet",
@@ -263,8 +387,9 @@ func (r *NovaComputeReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.No
 			Tolerations:        tolerations,
 			InitContainers: []corev1.Container{
 				{
-					Name:  "init",
-					Image: instance.Spec.NovaComputeImage,
+					Name:      "init",
+					Image:     instance.Spec.NovaComputeImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &runAsUser,
 						Privileged: &trueVar,
@@ -278,10 +403,13 @@ func (r *NovaComputeReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.No
 			},
 			Containers: []corev1.Container{
 				{
-					Name:           "nova-compute",
-					Image:          instance.Spec.NovaComputeImage,
-					ReadinessProbe: readinessProbe.GetProbe(),
-					LivenessProbe:  livenessProbe.GetProbe(),
+					Name:      "nova-compute",
+					Image:     instance.Spec.NovaComputeImage,
+					Resources: instance.Spec.Resources,
+					// heartbeat based readiness, the liveness only checks the connection to the message bus
+					StartupProbe:   common.GetHealthCheckStartupProbe("nova-compute", instance.Spec.Probes),
+					ReadinessProbe: common.GetHealthCheckReadinessProbe("nova-compute", instance.Spec.Probes),
+					LivenessProbe:  common.GetHealthCheckLivenessProbe("nova-compute", instance.Spec.Probes),
 					SecurityContext: &corev1.SecurityContext{
 						Privileged: &trueVar,
 						RunAsUser:  &runAsUser,
@@ -302,3 +430,74 @@ func (r *NovaComputeReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.No
 
 	return op, err
 }
+
+// updateNodeStatuses - updates the state of nova-compute on the nodes of the pods of the DaemonSet in the status
+func (r *NovaComputeReconciler) updateNodeStatuses(instance *novav1beta1.NovaCompute, services []common.NovaService) error {
+	daemonSet := &appsv1.DaemonSet{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, daemonSet)
+	if err != nil {
+		return err
+	}
+
+	// the pods of the current pod template carry the hash of the newest revision of the DaemonSet
+	revisions := &appsv1.ControllerRevisionList{}
+	err = r.Client.List(context.TODO(), revisions, client.InNamespace(instance.Namespace), client.MatchingLabels(common.GetLabels(instance.Name, novacompute.AppLabel)))
+	if err != nil {
+		return err
+	}
+	currentRevision := novacompute.GetCurrentRevision(daemonSet, revisions.Items)
+
+	pods := &corev1.PodList{}
+	err = r.Client.List(context.TODO(), pods, client.InNamespace(instance.Namespace), client.MatchingLabels(common.GetLabels(instance.Name, novacompute.AppLabel)))
+	if err != nil {
+		return err
+	}
+
+	nodes := map[string]*corev1.Node{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		node := &corev1.Node{}
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			nodes[pod.Spec.NodeName] = node
+		}
+	}
+
+	nodeStatuses := novacompute.GetNodeStatuses(pods.Items, nodes, currentRevision)
+	novacompute.SetServiceStates(nodeStatuses, services)
+	if instance.Status.CurrentRevision == currentRevision && reflect.DeepEqual(instance.Status.Nodes, nodeStatuses) {
+		return nil
+	}
+
+	instance.Status.CurrentRevision = currentRevision
+	instance.Status.Nodes = nodeStatuses
+	instance.Status.Count = int32(len(nodeStatuses))
+	return r.Client.Status().Update(context.TODO(), instance)
+}
+
+// getComputeServices - nova-compute services of the os-services API of the internal compute endpoint in the
+// catalog, as the nova service user or with the application credential if set
+func (r *NovaComputeReconciler) getComputeServices(instance *novav1beta1.NovaCompute, novaSecret *corev1.Secret, applicationCredentialSecret *corev1.Secret) ([]common.NovaService, error) {
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = common.GetDefaultHTTPClient()
+	}
+
+	selectors := common.GetPasswordSelectors(instance.Spec.PasswordSelectors)
+	credentials := common.GetKeystoneCredentials(instance.Spec.KeystoneAuthURL, string(novaSecret.Data[selectors.Service]), applicationCredentialSecret, instance.Spec.PasswordSelectors)
+	token, err := common.IssueKeystoneToken(httpClient, credentials)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := token.GetEndpoint("compute", "internal")
+	if err != nil {
+		return nil, err
+	}
+
+	return common.GetServices(httpClient, endpoint, token.ID, novacompute.AppLabel)
+}
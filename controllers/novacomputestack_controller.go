@@ -0,0 +1,519 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
+	libvirtd "github.com/openstack-k8s-operators/nova-operator/pkg/libvirtd"
+	novacomputestack "github.com/openstack-k8s-operators/nova-operator/pkg/novacomputestack"
+	virtlogd "github.com/openstack-k8s-operators/nova-operator/pkg/virtlogd"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NovaComputeStackReconciler reconciles a NovaComputeStack object
+type NovaComputeStackReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+}
+
+// GetClient -
+func (r *NovaComputeStackReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetLogger -
+func (r *NovaComputeStackReconciler) GetLogger() logr.Logger {
+	return r.Log
+}
+
+// GetScheme -
+func (r *NovaComputeStackReconciler) GetScheme() *runtime.Scheme {
+	return r.Scheme
+}
+
+// +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=novacomputestacks,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=novacomputestacks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=novacomputestacks/finalizers,verbs=update
+// +kubebuilder:rbac:groups=nova.openstack.org,namespace=openstack,resources=libvirtds;virtlogds;iscsids;novamigrationtargets;novacomputes,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=apps,namespace=openstack,resources=daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace=openstack,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch;update
+
+// Reconcile reconcile nova compute stack API requests
+func (r *NovaComputeStackReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	_ = r.Log.WithValues("novacomputestack", req.NamespacedName)
+
+	// Fetch the NovaComputeStack instance
+	instance := &novav1beta1.NovaComputeStack{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers. Return and don't requeue.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	// the hypervisor labels are on the cluster scoped nodes, they are not garbage collected
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(instance)
+	}
+	if !controllerutil.ContainsFinalizer(instance, novacomputestack.Finalizer) {
+		controllerutil.AddFinalizer(instance, novacomputestack.Finalizer)
+		if err := r.Client.Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// the daemons have to be restricted to the compute nodes
+	err = common.ValidateComputePlacement(instance.Spec.RoleName, instance.Spec.ComputePlacement)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPlacementCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	status := instance.Status.DeepCopy()
+
+	// Create or update the daemons nova-compute depends on, in a fixed order
+	for _, daemon := range []struct {
+		kind string
+		fn   func(*novav1beta1.NovaComputeStack) (controllerutil.OperationResult, error)
+	}{
+		{"Libvirtd", r.libvirtdCreateOrUpdate},
+		{"Virtlogd", r.virtlogdCreateOrUpdate},
+		{"Iscsid", r.iscsidCreateOrUpdate},
+		{"NovaMigrationTarget", r.migrationTargetCreateOrUpdate},
+	} {
+		op, err := daemon.fn(instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if op != controllerutil.OperationResultNone {
+			r.Log.Info(fmt.Sprintf("%s of %s successfully reconciled - operation: %s", daemon.kind, instance.Name, string(op)))
+		}
+	}
+
+	// nova-compute only gets scheduled to the nodes libvirtd and virtlogd are ready on
+	libvirtdPods, err := r.getPods(instance.Namespace, common.GetLabels(getComputeStackChildName(instance, novacomputestack.LibvirtdSuffix), libvirtd.AppLabel))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	virtlogdPods, err := r.getPods(instance.Namespace, common.GetLabels(getComputeStackChildName(instance, novacomputestack.VirtlogdSuffix), virtlogd.AppLabel))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	status.HypervisorNodes = novacomputestack.GetHypervisorNodes(instance.Status.HypervisorNodes, libvirtdPods, virtlogdPods)
+	if len(status.HypervisorNodes) == 0 {
+		r.Log.Info(fmt.Sprintf("Waiting for libvirtd and virtlogd of %s to get ready on a node", instance.Name))
+	}
+	err = r.setHypervisorLabels(instance, status.HypervisorNodes)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	op, err := r.novaComputeCreateOrUpdate(instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("NovaCompute of %s successfully reconciled - operation: %s", instance.Name, string(op)))
+	}
+
+	// aggregate the rollout state of the daemons
+	for _, daemon := range []struct {
+		status *novav1beta1.DaemonStatus
+		suffix string
+	}{
+		{&status.Libvirtd, novacomputestack.LibvirtdSuffix},
+		{&status.Virtlogd, novacomputestack.VirtlogdSuffix},
+		{&status.Iscsid, novacomputestack.IscsidSuffix},
+		{&status.NovaMigrationTarget, novacomputestack.NovaMigrationTargetSuffix},
+		{&status.NovaCompute, novacomputestack.NovaComputeSuffix},
+	} {
+		*daemon.status, err = r.getDaemonStatus(instance.Namespace, getComputeStackChildName(instance, daemon.suffix))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !reflect.DeepEqual(*status, instance.Status) {
+		instance.Status = *status
+		if err := r.Client.Status().Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, getComputeStackCondition(instance.Status))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDelete - remove the hypervisor labels of the stack from the nodes before the stack is deleted
+func (r *NovaComputeStackReconciler) reconcileDelete(instance *novav1beta1.NovaComputeStack) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, novacomputestack.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	err := r.setHypervisorLabels(instance, []string{})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(instance, novacomputestack.Finalizer)
+	err = r.Client.Update(context.TODO(), instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Log.Info(fmt.Sprintf("Hypervisor labels of %s removed from the nodes", instance.Name))
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager -
+func (r *NovaComputeStackReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// watch the DaemonSets of the daemons of a stack, their status reports the pod readiness
+	daemonSetFn := handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+		owner := metav1.GetControllerOf(o.Meta)
+		if owner == nil || owner.APIVersion != novav1beta1.GroupVersion.String() {
+			return []reconcile.Request{}
+		}
+
+		var daemon runtime.Object
+		switch owner.Kind {
+		case "Libvirtd":
+			daemon = &novav1beta1.Libvirtd{}
+		case "Virtlogd":
+			daemon = &novav1beta1.Virtlogd{}
+		case "Iscsid":
+			daemon = &novav1beta1.Iscsid{}
+		case "NovaMigrationTarget":
+			daemon = &novav1beta1.NovaMigrationTarget{}
+		case "NovaCompute":
+			daemon = &novav1beta1.NovaCompute{}
+		default:
+			return []reconcile.Request{}
+		}
+
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: owner.Name, Namespace: o.Meta.GetNamespace()}, daemon)
+		if err != nil {
+			if !k8s_errors.IsNotFound(err) {
+				r.Log.Error(err, "Unable to retrieve the owner of DaemonSet", "DaemonSet", o.Meta.GetName())
+			}
+			return []reconcile.Request{}
+		}
+
+		daemonMeta, err := meta.Accessor(daemon)
+		if err != nil {
+			return []reconcile.Request{}
+		}
+		stack := metav1.GetControllerOf(daemonMeta)
+		if stack == nil || stack.Kind != "NovaComputeStack" {
+			return []reconcile.Request{}
+		}
+
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: stack.Name, Namespace: o.Meta.GetNamespace()}},
+		}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&novav1beta1.NovaComputeStack{}).
+		Owns(&novav1beta1.Libvirtd{}).
+		Owns(&novav1beta1.Virtlogd{}).
+		Owns(&novav1beta1.Iscsid{}).
+		Owns(&novav1beta1.NovaMigrationTarget{}).
+		Owns(&novav1beta1.NovaCompute{}).
+		Watches(&source.Kind{Type: &appsv1.DaemonSet{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: daemonSetFn,
+			}).
+		Complete(r)
+}
+
+// getComputeStackChildName - name of the CR of a daemon of the stack
+func getComputeStackChildName(instance *novav1beta1.NovaComputeStack, suffix string) string {
+	return fmt.Sprintf("%s-%s", instance.Name, suffix)
+}
+
+// getComputeStackCondition - ComputeStack condition, ready once every daemon is ready on all its nodes
+func getComputeStackCondition(status novav1beta1.NovaComputeStackStatus) novav1beta1.Condition {
+	for _, daemon := range []novav1beta1.DaemonStatus{status.Libvirtd, status.Virtlogd, status.Iscsid, status.NovaMigrationTarget, status.NovaCompute} {
+		if daemon.Desired == 0 || daemon.Ready < daemon.Desired {
+			return novav1beta1.Condition{
+				Type:    novav1beta1.ComputeStackCondition,
+				Status:  corev1.ConditionFalse,
+				Reason:  novav1beta1.ComputeStackProgressingReason,
+				Message: fmt.Sprintf("%s is ready on %d of %d nodes", daemon.Name, daemon.Ready, daemon.Desired),
+			}
+		}
+	}
+
+	return novav1beta1.Condition{
+		Type:    novav1beta1.ComputeStackCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  novav1beta1.ComputeStackReadyReason,
+		Message: fmt.Sprintf("Compute stack is ready on %d nodes", status.NovaCompute.Ready),
+	}
+}
+
+func (r *NovaComputeStackReconciler) getPods(namespace string, labels map[string]string) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	err := r.Client.List(context.TODO(), pods, client.InNamespace(namespace), client.MatchingLabels(labels))
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func (r *NovaComputeStackReconciler) getDaemonStatus(namespace string, name string) (novav1beta1.DaemonStatus, error) {
+	daemonStatus := novav1beta1.DaemonStatus{Name: name}
+
+	daemonSet := &appsv1.DaemonSet{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, daemonSet)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return daemonStatus, nil
+		}
+		return daemonStatus, err
+	}
+
+	daemonStatus.Desired = daemonSet.Status.DesiredNumberScheduled
+	daemonStatus.Ready = daemonSet.Status.NumberReady
+	return daemonStatus, nil
+}
+
+func (r *NovaComputeStackReconciler) libvirtdCreateOrUpdate(instance *novav1beta1.NovaComputeStack) (controllerutil.OperationResult, error) {
+	daemon := &novav1beta1.Libvirtd{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getComputeStackChildName(instance, novacomputestack.LibvirtdSuffix),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.Client, daemon, func() error {
+		daemon.Spec = novav1beta1.LibvirtdSpec{
+			NovaLibvirtImage:  instance.Spec.NovaLibvirtImage,
+			RoleName:          instance.Spec.RoleName,
+			ComputePlacement:  instance.Spec.ComputePlacement,
+			Resources:         instance.Spec.LibvirtdResources,
+			PriorityClassName: instance.Spec.PriorityClassName,
+		}
+
+		return controllerutil.SetControllerReference(instance, daemon, r.Scheme)
+	})
+}
+
+func (r *NovaComputeStackReconciler) virtlogdCreateOrUpdate(instance *novav1beta1.NovaComputeStack) (controllerutil.OperationResult, error) {
+	daemon := &novav1beta1.Virtlogd{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getComputeStackChildName(instance, novacomputestack.VirtlogdSuffix),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.Client, daemon, func() error {
+		daemon.Spec = novav1beta1.VirtlogdSpec{
+			NovaLibvirtImage:  instance.Spec.NovaLibvirtImage,
+			RoleName:          instance.Spec.RoleName,
+			ComputePlacement:  instance.Spec.ComputePlacement,
+			Resources:         instance.Spec.VirtlogdResources,
+			PriorityClassName: instance.Spec.PriorityClassName,
+		}
+
+		return controllerutil.SetControllerReference(instance, daemon, r.Scheme)
+	})
+}
+
+func (r *NovaComputeStackReconciler) iscsidCreateOrUpdate(instance *novav1beta1.NovaComputeStack) (controllerutil.OperationResult, error) {
+	daemon := &novav1beta1.Iscsid{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getComputeStackChildName(instance, novacomputestack.IscsidSuffix),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.Client, daemon, func() error {
+		daemon.Spec = novav1beta1.IscsidSpec{
+			IscsidImage:       instance.Spec.IscsidImage,
+			RoleName:          instance.Spec.RoleName,
+			ComputePlacement:  instance.Spec.ComputePlacement,
+			Resources:         instance.Spec.IscsidResources,
+			PriorityClassName: instance.Spec.PriorityClassName,
+		}
+
+		return controllerutil.SetControllerReference(instance, daemon, r.Scheme)
+	})
+}
+
+func (r *NovaComputeStackReconciler) migrationTargetCreateOrUpdate(instance *novav1beta1.NovaComputeStack) (controllerutil.OperationResult, error) {
+	daemon := &novav1beta1.NovaMigrationTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getComputeStackChildName(instance, novacomputestack.NovaMigrationTargetSuffix),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.Client, daemon, func() error {
+		daemon.Spec = novav1beta1.NovaMigrationTargetSpec{
+			NovaComputeImage:  instance.Spec.NovaComputeImage,
+			SshdPort:          instance.Spec.SshdPort,
+			RoleName:          instance.Spec.RoleName,
+			ComputePlacement:  instance.Spec.ComputePlacement,
+			Resources:         instance.Spec.NovaMigrationTargetResources,
+			PriorityClassName: instance.Spec.PriorityClassName,
+		}
+
+		return controllerutil.SetControllerReference(instance, daemon, r.Scheme)
+	})
+}
+
+// setHypervisorLabels - label the hypervisor nodes with the name of the stack and remove the label
+// from the nodes which are no longer hypervisor nodes, nova-compute gets evicted from them
+func (r *NovaComputeStackReconciler) setHypervisorLabels(instance *novav1beta1.NovaComputeStack, hypervisorNodes []string) error {
+	label := novacomputestack.GetHypervisorLabel(instance.Namespace)
+
+	// the label without the namespace prefix of older versions
+	nodes := &corev1.NodeList{}
+	err := r.Client.List(context.TODO(), nodes, client.MatchingLabels{novacomputestack.HypervisorLabel: instance.Name})
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes.Items {
+		if err := r.patchHypervisorLabel(node.Name, novacomputestack.HypervisorLabel, ""); err != nil {
+			return err
+		}
+	}
+
+	nodes = &corev1.NodeList{}
+	err = r.Client.List(context.TODO(), nodes, client.MatchingLabels{label: instance.Name})
+	if err != nil {
+		return err
+	}
+	labeledNodes := []string{}
+	for _, node := range nodes.Items {
+		labeledNodes = append(labeledNodes, node.Name)
+	}
+
+	add, remove := novacomputestack.GetHypervisorLabelChanges(labeledNodes, hypervisorNodes)
+	for _, name := range add {
+		r.Log.Info(fmt.Sprintf("Node %s got a hypervisor of %s, labeling it", name, instance.Name))
+		if err := r.patchHypervisorLabel(name, label, instance.Name); err != nil {
+			return err
+		}
+	}
+	for _, name := range remove {
+		r.Log.Info(fmt.Sprintf("Node %s is no longer a hypervisor of %s, removing its label", name, instance.Name))
+		if err := r.patchHypervisorLabel(name, label, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchHypervisorLabel - set the hypervisor label of the node to the stack name, or remove it if empty
+func (r *NovaComputeStackReconciler) patchHypervisorLabel(name string, label string, stackName string) error {
+	node := &corev1.Node{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name}, node)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if stackName == "" {
+		delete(node.Labels, label)
+	} else {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[label] = stackName
+	}
+	return r.Client.Patch(context.TODO(), node, patch)
+}
+
+func (r *NovaComputeStackReconciler) novaComputeCreateOrUpdate(instance *novav1beta1.NovaComputeStack) (controllerutil.OperationResult, error) {
+	daemon := &novav1beta1.NovaCompute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getComputeStackChildName(instance, novacomputestack.NovaComputeSuffix),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.Client, daemon, func() error {
+		// nova-compute only runs on the hypervisor nodes of the stack
+		placement := instance.Spec.ComputePlacement
+		placement.NodeSelector = map[string]string{}
+		for k, v := range instance.Spec.NodeSelector {
+			placement.NodeSelector[k] = v
+		}
+		placement.NodeSelector[novacomputestack.GetHypervisorLabel(instance.Namespace)] = instance.Name
+
+		daemon.Spec = novav1beta1.NovaComputeSpec{
+			NovaComputeImage:            instance.Spec.NovaComputeImage,
+			NovaComputeCPUSharedSet:     instance.Spec.NovaComputeCPUSharedSet,
+			NovaComputeCPUDedicatedSet:  instance.Spec.NovaComputeCPUDedicatedSet,
+			RoleName:                    instance.Spec.RoleName,
+			ComputePlacement:            placement,
+			Resources:                   instance.Spec.NovaComputeResources,
+			PriorityClassName:           instance.Spec.PriorityClassName,
+			Cell:                        instance.Spec.Cell,
+			NovaSecret:                  instance.Spec.NovaSecret,
+			PlacementSecret:             instance.Spec.PlacementSecret,
+			NeutronSecret:               instance.Spec.NeutronSecret,
+			ApplicationCredentialSecret: instance.Spec.ApplicationCredentialSecret,
+			TransportURLSecret:          instance.Spec.TransportURLSecret,
+			Notifications:               instance.Spec.Notifications,
+			PasswordSelectors:           instance.Spec.PasswordSelectors,
+			Probes:                      instance.Spec.NovaComputeProbes,
+			ReportServiceState:          instance.Spec.ReportServiceState,
+			KeystoneAuthURL:             instance.Spec.KeystoneAuthURL,
+		}
+
+		return controllerutil.SetControllerReference(instance, daemon, r.Scheme)
+	})
+}
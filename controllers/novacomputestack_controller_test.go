@@ -0,0 +1,109 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	novacomputestack "github.com/openstack-k8s-operators/nova-operator/pkg/novacomputestack"
+)
+
+// newTestNovaComputeStackReconciler - NovaComputeStackReconciler with a fake client holding the objects
+func newTestNovaComputeStackReconciler(objs ...runtime.Object) *NovaComputeStackReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = novav1beta1.AddToScheme(scheme)
+	return &NovaComputeStackReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, objs...),
+		Log:    ctrl.Log.WithName("test"),
+		Scheme: scheme,
+	}
+}
+
+func newTestNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+// getTestNodeLabels - labels of the node
+func getTestNodeLabels(t *testing.T, r *NovaComputeStackReconciler, name string) map[string]string {
+	node := &corev1.Node{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: name}, node))
+	return node.Labels
+}
+
+func TestSetHypervisorLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaComputeStack{ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "openstack"}}
+	label := "openstack.nova.openstack.org/hypervisor"
+	otherLabel := "edge.nova.openstack.org/hypervisor"
+	r := newTestNovaComputeStackReconciler(
+		// label without the namespace of older versions
+		newTestNode("worker-0", map[string]string{novacomputestack.HypervisorLabel: "compute"}),
+		// hypervisor of the stack with the same name in another namespace
+		newTestNode("worker-1", map[string]string{otherLabel: "compute"}),
+		newTestNode("worker-2", map[string]string{label: "compute"}),
+	)
+
+	assert.NoError(r.setHypervisorLabels(instance, []string{"worker-0", "worker-1"}))
+	assert.Equal(map[string]string{label: "compute"}, getTestNodeLabels(t, r, "worker-0"))
+	assert.Equal(map[string]string{otherLabel: "compute", label: "compute"}, getTestNodeLabels(t, r, "worker-1"))
+	assert.Empty(getTestNodeLabels(t, r, "worker-2"))
+
+	// removing the stack from all nodes keeps the labels of the other namespace
+	assert.NoError(r.setHypervisorLabels(instance, []string{}))
+	assert.Empty(getTestNodeLabels(t, r, "worker-0"))
+	assert.Equal(map[string]string{otherLabel: "compute"}, getTestNodeLabels(t, r, "worker-1"))
+}
+
+func TestNovaComputeStackFinalizer(t *testing.T) {
+	assert := assert.New(t)
+
+	name := types.NamespacedName{Name: "compute", Namespace: "openstack"}
+	label := "openstack.nova.openstack.org/hypervisor"
+	instance := &novav1beta1.NovaComputeStack{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}
+	r := newTestNovaComputeStackReconciler(instance, newTestNode("worker-0", map[string]string{label: "compute"}))
+
+	// the finalizer gets added on the first reconcile
+	_, err := r.Reconcile(ctrl.Request{NamespacedName: name})
+	assert.NoError(err)
+	assert.NoError(r.Client.Get(context.TODO(), name, instance))
+	assert.True(controllerutil.ContainsFinalizer(instance, novacomputestack.Finalizer))
+
+	// and removed with the labels on deletion
+	now := metav1.Now()
+	instance.DeletionTimestamp = &now
+	assert.NoError(r.Client.Update(context.TODO(), instance))
+	_, err = r.Reconcile(ctrl.Request{NamespacedName: name})
+	assert.NoError(err)
+	assert.Empty(getTestNodeLabels(t, r, "worker-0"))
+	instance = &novav1beta1.NovaComputeStack{}
+	assert.NoError(r.Client.Get(context.TODO(), name, instance))
+	assert.False(controllerutil.ContainsFinalizer(instance, novacomputestack.Finalizer))
+}
@@ -39,6 +39,7 @@ import (
 	novaconductor "github.com/openstack-k8s-operators/nova-operator/pkg/novaconductor"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 )
 
 // NovaConductorReconciler reconciles a NovaConductor object
@@ -68,6 +69,8 @@ func (r *NovaConductorReconciler) GetScheme() *runtime.Scheme {
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novaconductors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;update;delete;
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
 
 // Reconcile - nova conductor
 func (r *NovaConductorReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -94,10 +97,16 @@ func (r *NovaConductorReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 	hashes := []novav1beta1.Hash{}
 	secretHashes, err := common.GetSecretsFromCR(r, instance, instance.Namespace, instance.Spec, &envVars)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	hashes = append(hashes, secretHashes...)
 
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// check for required configMaps
 	configMaps := []string{
 		fmt.Sprintf("%s-scripts", instance.Spec.ManagingCrName),            //ScriptsConfigMap
@@ -116,9 +125,18 @@ func (r *NovaConductorReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{RequeueAfter: time.Second * 10}, err
 	}
 
+	// keep replicas available while nodes get drained
+	op, err := common.EnsurePodDisruptionBudget(r, instance, instance.Spec.Replicas, common.GetLabels(instance.Name, novaconductor.AppLabel), instance.Spec.PodDisruptionBudget)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("PodDisruptionBudget %s successfully reconciled - operation: %s", instance.Name, string(op)))
+	}
+
 	// nova-conductor
 	// Create or update the Deployment object
-	op, err := r.statefulsetCreateOrUpdate(instance, envVars)
+	op, err = r.statefulsetCreateOrUpdate(instance, envVars)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -132,6 +150,10 @@ func (r *NovaConductorReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 
 // SetupWithManager -
 func (r *NovaConductorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.NovaConductor{}); err != nil {
+		return err
+	}
 
 	// watch for configmap where the CM upper-cr label AND the CR.Spec.ManagingCrName label matches
 	configMapFn := handler.ToRequestsFunc(func(cm handler.MapObject) []reconcile.Request {
@@ -172,11 +194,17 @@ func (r *NovaConductorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.NovaConductor{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		// watch the config CMs we don't own
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
 			&handler.EnqueueRequestsFromMapFunc{
 				ToRequests: configMapFn,
 			}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaConductorList{}),
+			}).
 		Complete(r)
 
 }
@@ -188,15 +216,15 @@ func (r *NovaConductorReconciler) statefulsetCreateOrUpdate(instance *novav1beta
 	envVars["KOLLA_CONFIG_FILE"] = util.EnvValue(novaconductor.KollaConfig)
 	envVars["KOLLA_CONFIG_STRATEGY"] = util.EnvValue("COPY_ALWAYS")
 
-	// get readinessProbes
-	readinessProbe := util.Probe{ProbeType: "readiness"}
-	livenessProbe := util.Probe{ProbeType: "liveness"}
-
 	// get volumes
 	initVolumeMounts := common.GetInitVolumeMounts()
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(instance.Spec.ManagingCrName)
 
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(instance.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(instance.Spec.DatabaseTLS)...)
+
 	statefulset := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -227,17 +255,25 @@ func (r *NovaConductorReconciler) statefulsetCreateOrUpdate(instance *novav1beta
 
 		statefulset.Spec.Replicas = &instance.Spec.Replicas
 		statefulset.Spec.Template.Spec = corev1.PodSpec{
-			ServiceAccountName: serviceAccountName,
-			Volumes:            volumes,
+			ServiceAccountName:        serviceAccountName,
+			PriorityClassName:         instance.Spec.PriorityClassName,
+			NodeSelector:              instance.Spec.NodeSelector,
+			Tolerations:               instance.Spec.Tolerations,
+			Affinity:                  common.GetPodAffinity(instance.Spec.Affinity, common.GetLabels(instance.Name, novaconductor.AppLabel)),
+			TopologySpreadConstraints: instance.Spec.TopologySpreadConstraints,
+			Volumes:                   volumes,
 			Containers: []corev1.Container{
 				{
-					Name:  "nova-conductor",
-					Image: instance.Spec.ContainerImage,
+					Name:      "nova-conductor",
+					Image:     instance.Spec.ContainerImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser: &runAsUser,
 					},
-					ReadinessProbe: readinessProbe.GetProbe(),
-					LivenessProbe:  livenessProbe.GetProbe(),
+					// heartbeat based readiness, the liveness only checks the connection to the message bus
+					StartupProbe:   common.GetHealthCheckStartupProbe("nova-conductor", instance.Spec.Probes),
+					ReadinessProbe: common.GetHealthCheckReadinessProbe("nova-conductor", instance.Spec.Probes),
+					LivenessProbe:  common.GetHealthCheckLivenessProbe("nova-conductor", instance.Spec.Probes),
 					Env:            envs,
 					VolumeMounts:   volumeMounts,
 				},
@@ -252,15 +288,21 @@ func (r *NovaConductorReconciler) statefulsetCreateOrUpdate(instance *novav1beta
 
 		}
 		initContainerDetails := common.CtrlInitContainer{
-			ContainerImage:     instance.Spec.ContainerImage,
-			DatabaseHost:       instance.Spec.DatabaseHostname,
-			CellDatabase:       fmt.Sprintf("%s_%s", novaconductor.DatabasePrefix, instance.Spec.Cell),
-			APIDatabase:        fmt.Sprintf("%s_%s", novaconductor.DatabasePrefix, novaconductor.APIDatabase),
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			VolumeMounts:       initVolumeMounts,
+			ContainerImage:                  instance.Spec.ContainerImage,
+			DatabaseHost:                    instance.Spec.DatabaseHostname,
+			CellDatabase:                    fmt.Sprintf("%s_%s", novaconductor.DatabasePrefix, instance.Spec.Cell),
+			APIDatabase:                     fmt.Sprintf("%s_%s", novaconductor.DatabasePrefix, novaconductor.APIDatabase),
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    instance.Spec.DatabaseUser,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.NotificationsTransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			NovaSecret:                      instance.Spec.NovaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			VolumeMounts:                    initVolumeMounts,
+			Resources:                       instance.Spec.Resources,
 		}
 
 		statefulset.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
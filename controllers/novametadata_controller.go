@@ -38,7 +38,9 @@ import (
 	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
 	novametadata "github.com/openstack-k8s-operators/nova-operator/pkg/novametadata"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 )
 
 // NovaMetadataReconciler reconciles a NovaMetadata object
@@ -66,6 +68,10 @@ func (r *NovaMetadataReconciler) GetScheme() *runtime.Scheme {
 
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novametadata,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novametadata/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;
 
 // Reconcile - nova metadata
 func (r *NovaMetadataReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -91,10 +97,30 @@ func (r *NovaMetadataReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 	hashes := []novav1beta1.Hash{}
 	secretHashes, err := common.GetSecretsFromCR(r, instance, instance.Namespace, instance.Spec, &envVars)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	hashes = append(hashes, secretHashes...)
 
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// validate the policy overrides and write them to the policy configmap mounted into the pods
+	policyHash, err := common.EnsurePolicy(r, instance, instance.Spec.Policy)
+	if err != nil {
+		return common.HandlePolicyError(r, instance, &instance.Status.Conditions, err)
+	}
+	if policyHash != "" {
+		envVars[common.GetPolicyConfigMapName(instance.Name)] = util.EnvValue(policyHash)
+		hashes = append(hashes, novav1beta1.Hash{Name: common.GetPolicyConfigMapName(instance.Name), Hash: policyHash})
+	}
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPolicyCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// check for required configMaps
 	configMaps := []string{
 		fmt.Sprintf("%s-scripts", instance.Spec.ManagingCrName),            //ScriptsConfigMap
@@ -113,9 +139,27 @@ func (r *NovaMetadataReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		return ctrl.Result{RequeueAfter: time.Second * 10}, err
 	}
 
+	// keep replicas available while nodes get drained
+	op, err := common.EnsurePodDisruptionBudget(r, instance, common.GetMinReplicas(instance.Spec.Replicas, instance.Spec.Autoscaling), common.GetLabels(instance.Name, novametadata.AppLabel), instance.Spec.PodDisruptionBudget)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("PodDisruptionBudget %s successfully reconciled - operation: %s", instance.Name, string(op)))
+	}
+
+	// scale the replicas with the load if autoscaling is enabled
+	op, err = common.EnsureHorizontalPodAutoscaler(r, instance, instance.Spec.Replicas, instance.Spec.Autoscaling)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("HorizontalPodAutoscaler %s successfully reconciled - operation: %s", instance.Name, string(op)))
+	}
+
 	// nova-metadata
 	// Create or update the Deployment object
-	op, err := r.deploymentCreateOrUpdate(instance, envVars)
+	op, err = r.deploymentCreateOrUpdate(instance, envVars)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -129,6 +173,11 @@ func (r *NovaMetadataReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 
 // SetupWithManager -
 func (r *NovaMetadataReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.NovaMetadata{}); err != nil {
+		return err
+	}
+
 	// watch for configmap where the CM upper-cr label AND the CR.Spec.ManagingCrName label matches
 	configMapFn := handler.ToRequestsFunc(func(cm handler.MapObject) []reconcile.Request {
 		result := []reconcile.Request{}
@@ -159,6 +208,17 @@ func (r *NovaMetadataReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				}
 			}
 		}
+		// the policy CMs referenced in the spec
+		for _, cr := range metadatas.Items {
+			if cr.Spec.Policy.ConfigMap != "" && cr.Spec.Policy.ConfigMap == cm.Meta.GetName() {
+				name := client.ObjectKey{
+					Namespace: cm.Meta.GetNamespace(),
+					Name:      cr.Name,
+				}
+				r.Log.Info(fmt.Sprintf("Policy ConfigMap object %s referenced by CR %s", cm.Meta.GetName(), cr.Name))
+				result = append(result, reconcile.Request{NamespacedName: name})
+			}
+		}
 		if len(result) > 0 {
 			return result
 		}
@@ -168,11 +228,18 @@ func (r *NovaMetadataReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.NovaMetadata{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2beta2.HorizontalPodAutoscaler{}).
 		// watch the config CMs we don't own
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
 			&handler.EnqueueRequestsFromMapFunc{
 				ToRequests: configMapFn,
 			}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaMetadataList{}),
+			}).
 		Complete(r)
 }
 
@@ -193,6 +260,14 @@ func (r *NovaMetadataReconciler) deploymentCreateOrUpdate(instance *novav1beta1.
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(instance.Spec.ManagingCrName)
 
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(instance.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(instance.Spec.DatabaseTLS)...)
+
+	// policy overrides if a policy is set
+	volumeMounts = append(volumeMounts, common.GetPolicyVolumeMounts(instance.Spec.Policy)...)
+	volumes = append(volumes, common.GetPolicyVolumes(instance.Name, instance.Spec.Policy)...)
+
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -221,14 +296,23 @@ func (r *NovaMetadataReconciler) deploymentCreateOrUpdate(instance *novav1beta1.
 			deployment.Spec.Template.Labels[k] = v
 		}
 
-		deployment.Spec.Replicas = &instance.Spec.Replicas
+		// the HorizontalPodAutoscaler owns the replicas while autoscaling is enabled
+		if !instance.Spec.Autoscaling.Enabled || deployment.Spec.Replicas == nil {
+			deployment.Spec.Replicas = &instance.Spec.Replicas
+		}
 		deployment.Spec.Template.Spec = corev1.PodSpec{
-			ServiceAccountName: serviceAccountName,
-			Volumes:            volumes,
+			ServiceAccountName:        serviceAccountName,
+			PriorityClassName:         instance.Spec.PriorityClassName,
+			NodeSelector:              instance.Spec.NodeSelector,
+			Tolerations:               instance.Spec.Tolerations,
+			Affinity:                  common.GetPodAffinity(instance.Spec.Affinity, common.GetLabels(instance.Name, novametadata.AppLabel)),
+			TopologySpreadConstraints: instance.Spec.TopologySpreadConstraints,
+			Volumes:                   volumes,
 			Containers: []corev1.Container{
 				{
-					Name:  "nova-metadata",
-					Image: instance.Spec.ContainerImage,
+					Name:      "nova-metadata",
+					Image:     instance.Spec.ContainerImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser: &runAsUser,
 					},
@@ -242,15 +326,21 @@ func (r *NovaMetadataReconciler) deploymentCreateOrUpdate(instance *novav1beta1.
 		}
 
 		initContainerDetails := common.CtrlInitContainer{
-			ContainerImage:     instance.Spec.ContainerImage,
-			DatabaseHost:       instance.Spec.DatabaseHostname,
-			CellDatabase:       fmt.Sprintf("%s_%s", novametadata.DatabasePrefix, instance.Spec.Cell),
-			APIDatabase:        fmt.Sprintf("%s_%s", novametadata.DatabasePrefix, novametadata.APIDatabase),
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			VolumeMounts:       initVolumeMounts,
+			ContainerImage:                  instance.Spec.ContainerImage,
+			DatabaseHost:                    instance.Spec.DatabaseHostname,
+			CellDatabase:                    fmt.Sprintf("%s_%s", novametadata.DatabasePrefix, instance.Spec.Cell),
+			APIDatabase:                     fmt.Sprintf("%s_%s", novametadata.DatabasePrefix, novametadata.APIDatabase),
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    instance.Spec.DatabaseUser,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.NotificationsTransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			NovaSecret:                      instance.Spec.NovaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			VolumeMounts:                    initVolumeMounts,
+			Resources:                       instance.Spec.Resources,
 		}
 		deployment.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
 
@@ -88,6 +88,16 @@ func (r *NovaMigrationTargetReconciler) Reconcile(req ctrl.Request) (ctrl.Result
 		return ctrl.Result{}, err
 	}
 
+	// the daemons have to be restricted to the compute nodes
+	err = common.ValidateComputePlacement(instance.Spec.RoleName, instance.Spec.ComputePlacement)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPlacementCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	envVars := make(map[string]util.EnvSetter)
 
 	// Create/update configmaps from templates
@@ -208,7 +218,7 @@ func (r *NovaMigrationTargetReconciler) daemonsetCreateOrUpdate(instance *novav1
 	// tolerations
 	tolerations := []corev1.Toleration{}
 	// add compute worker nodes tolerations
-	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName) {
+	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName, instance.Spec.Tolerations) {
 		tolerations = append(tolerations, toleration)
 	}
 
@@ -247,7 +257,9 @@ func (r *NovaMigrationTargetReconciler) daemonsetCreateOrUpdate(instance *novav1
 
 		daemonSet.Spec.Template.Spec = corev1.PodSpec{
 			ServiceAccountName: serviceAccountName,
-			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName),
+			PriorityClassName:  instance.Spec.PriorityClassName,
+			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName, instance.Spec.NodeSelector),
+			Affinity:           common.GetComputeWorkerAffinity(instance.Spec.NodeAffinity),
 			HostIPC:            true,
 			HostNetwork:        true,
 			DNSPolicy:          "ClusterFirstWithHostNet",
@@ -255,8 +267,9 @@ func (r *NovaMigrationTargetReconciler) daemonsetCreateOrUpdate(instance *novav1
 			Tolerations:        tolerations,
 			InitContainers: []corev1.Container{
 				{
-					Name:  "init",
-					Image: instance.Spec.NovaComputeImage,
+					Name:      "init",
+					Image:     instance.Spec.NovaComputeImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &runAsUser,
 						Privileged: &trueVar,
@@ -272,6 +285,7 @@ func (r *NovaMigrationTargetReconciler) daemonsetCreateOrUpdate(instance *novav1
 				{
 					Name:           "nova-migration-target",
 					Image:          instance.Spec.NovaComputeImage,
+					Resources:      instance.Spec.Resources,
 					ReadinessProbe: readinessProbe.GetProbe(),
 					LivenessProbe:  livenessProbe.GetProbe(),
 					SecurityContext: &corev1.SecurityContext{
@@ -39,6 +39,7 @@ import (
 	novanovncproxy "github.com/openstack-k8s-operators/nova-operator/pkg/novanovncproxy"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 )
 
 // NovaNoVNCProxyReconciler reconciles a NovaNoVNCProxy object
@@ -66,6 +67,8 @@ func (r *NovaNoVNCProxyReconciler) GetScheme() *runtime.Scheme {
 
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novanovncproxies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novanovncproxies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
 
 // Reconcile - nova noVNCproxy
 func (r *NovaNoVNCProxyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -91,10 +94,16 @@ func (r *NovaNoVNCProxyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, err
 	hashes := []novav1beta1.Hash{}
 	secretHashes, err := common.GetSecretsFromCR(r, instance, instance.Namespace, instance.Spec, &envVars)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	hashes = append(hashes, secretHashes...)
 
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// check for required configMaps
 	configMaps := []string{
 		fmt.Sprintf("%s-scripts", instance.Spec.ManagingCrName),            //ScriptsConfigMap
@@ -113,9 +122,18 @@ func (r *NovaNoVNCProxyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, err
 		return ctrl.Result{RequeueAfter: time.Second * 10}, err
 	}
 
+	// keep replicas available while nodes get drained
+	op, err := common.EnsurePodDisruptionBudget(r, instance, instance.Spec.Replicas, common.GetLabels(instance.Name, novanovncproxy.AppLabel), instance.Spec.PodDisruptionBudget)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("PodDisruptionBudget %s successfully reconciled - operation: %s", instance.Name, string(op)))
+	}
+
 	// nova-novncproxy
 	// Create or update the Deployment object
-	op, err := r.deploymentCreateOrUpdate(instance, envVars)
+	op, err = r.deploymentCreateOrUpdate(instance, envVars)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -129,6 +147,11 @@ func (r *NovaNoVNCProxyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, err
 
 // SetupWithManager -
 func (r *NovaNoVNCProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.NovaNoVNCProxy{}); err != nil {
+		return err
+	}
+
 	// watch for configmap where the CM upper-cr label AND the CR.Spec.ManagingCrName label matches
 	configMapFn := handler.ToRequestsFunc(func(cm handler.MapObject) []reconcile.Request {
 		result := []reconcile.Request{}
@@ -168,11 +191,17 @@ func (r *NovaNoVNCProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.NovaNoVNCProxy{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		// watch the config CMs we don't own
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
 			&handler.EnqueueRequestsFromMapFunc{
 				ToRequests: configMapFn,
 			}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaNoVNCProxyList{}),
+			}).
 		Complete(r)
 }
 
@@ -192,6 +221,10 @@ func (r *NovaNoVNCProxyReconciler) deploymentCreateOrUpdate(instance *novav1beta
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(instance.Spec.ManagingCrName)
 
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(instance.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(instance.Spec.DatabaseTLS)...)
+
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -223,12 +256,18 @@ func (r *NovaNoVNCProxyReconciler) deploymentCreateOrUpdate(instance *novav1beta
 
 		deployment.Spec.Replicas = &instance.Spec.Replicas
 		deployment.Spec.Template.Spec = corev1.PodSpec{
-			ServiceAccountName: serviceAccountName,
-			Volumes:            volumes,
+			ServiceAccountName:        serviceAccountName,
+			PriorityClassName:         instance.Spec.PriorityClassName,
+			NodeSelector:              instance.Spec.NodeSelector,
+			Tolerations:               instance.Spec.Tolerations,
+			Affinity:                  common.GetPodAffinity(instance.Spec.Affinity, common.GetLabels(instance.Name, novanovncproxy.AppLabel)),
+			TopologySpreadConstraints: instance.Spec.TopologySpreadConstraints,
+			Volumes:                   volumes,
 			Containers: []corev1.Container{
 				{
-					Name:  "nova-novncproxy",
-					Image: instance.Spec.ContainerImage,
+					Name:      "nova-novncproxy",
+					Image:     instance.Spec.ContainerImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser: &runAsUser,
 					},
@@ -241,15 +280,21 @@ func (r *NovaNoVNCProxyReconciler) deploymentCreateOrUpdate(instance *novav1beta
 		}
 
 		initContainerDetails := common.CtrlInitContainer{
-			ContainerImage:     instance.Spec.ContainerImage,
-			DatabaseHost:       instance.Spec.DatabaseHostname,
-			CellDatabase:       fmt.Sprintf("%s_%s", novanovncproxy.DatabasePrefix, instance.Spec.Cell),
-			APIDatabase:        fmt.Sprintf("%s_%s", novanovncproxy.DatabasePrefix, novanovncproxy.APIDatabase),
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			VolumeMounts:       initVolumeMounts,
+			ContainerImage:                  instance.Spec.ContainerImage,
+			DatabaseHost:                    instance.Spec.DatabaseHostname,
+			CellDatabase:                    fmt.Sprintf("%s_%s", novanovncproxy.DatabasePrefix, instance.Spec.Cell),
+			APIDatabase:                     fmt.Sprintf("%s_%s", novanovncproxy.DatabasePrefix, novanovncproxy.APIDatabase),
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    instance.Spec.DatabaseUser,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.NotificationsTransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			NovaSecret:                      instance.Spec.NovaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			VolumeMounts:                    initVolumeMounts,
+			Resources:                       instance.Spec.Resources,
 		}
 		deployment.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
 
@@ -40,6 +40,7 @@ import (
 	novascheduler "github.com/openstack-k8s-operators/nova-operator/pkg/novascheduler"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 )
 
 // NovaSchedulerReconciler reconciles a NovaScheduler object
@@ -68,7 +69,10 @@ func (r *NovaSchedulerReconciler) GetScheme() *runtime.Scheme {
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novaschedulers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nova.openstack.org,resources=novaschedulers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;
-// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;update;delete;
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;delete;
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete;
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
 
 // Reconcile - nova scheduler
 func (r *NovaSchedulerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -94,10 +98,16 @@ func (r *NovaSchedulerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 	hashes := []novav1beta1.Hash{}
 	secretHashes, err := common.GetSecretsFromCR(r, instance, instance.Namespace, instance.Spec, &envVars)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return common.HandleSecretError(r, instance, &instance.Status.Conditions, err)
 	}
 	hashes = append(hashes, secretHashes...)
 
+	// all referenced secrets contain the required keys
+	err = common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetSecretCondition(nil))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// check for required configMaps
 	configMaps := []string{
 		fmt.Sprintf("%s-scripts", instance.Spec.ManagingCrName),            //ScriptsConfigMap
@@ -116,22 +126,41 @@ func (r *NovaSchedulerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{RequeueAfter: time.Second * 10}, err
 	}
 
+	// keep replicas available while nodes get drained
+	op, err := common.EnsurePodDisruptionBudget(r, instance, instance.Spec.Replicas, common.GetLabels(instance.Name, novascheduler.AppLabel), instance.Spec.PodDisruptionBudget)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("PodDisruptionBudget %s successfully reconciled - operation: %s", instance.Name, string(op)))
+	}
+
 	// nova-scheduler
 	// Create or update the Deployment object
-	op, err := r.statefulsetCreateOrUpdate(instance, envVars)
+	op, err = r.deploymentCreateOrUpdate(instance, envVars)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if op != controllerutil.OperationResultNone {
-		r.Log.Info(fmt.Sprintf("StatefulSet %s successfully reconciled - operation: %s", instance.Name, string(op)))
+		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
 		return ctrl.Result{}, nil
 	}
 
+	// nova-scheduler was deployed as a StatefulSet before, which can not honor a maxUnavailable
+	err = r.Client.Delete(context.TODO(), &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace}})
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager -
 func (r *NovaSchedulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index the CRs by the secrets referenced in the spec to watch them
+	if err := common.SetupSecretIndex(mgr, &novav1beta1.NovaScheduler{}); err != nil {
+		return err
+	}
 
 	// watch for configmap where the CM upper-cr label AND the CR.Spec.ManagingCrName label matches
 	configMapFn := handler.ToRequestsFunc(func(cm handler.MapObject) []reconcile.Request {
@@ -172,72 +201,92 @@ func (r *NovaSchedulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&novav1beta1.NovaScheduler{}).
-		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		// watch the config CMs we don't own
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
 			&handler.EnqueueRequestsFromMapFunc{
 				ToRequests: configMapFn,
 			}).
+		// watch the secrets referenced in the spec
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: common.GetSecretWatchFn(r, &novav1beta1.NovaSchedulerList{}),
+			}).
 		Complete(r)
 }
 
-func (r *NovaSchedulerReconciler) statefulsetCreateOrUpdate(instance *novav1beta1.NovaScheduler, envVars map[string]util.EnvSetter) (controllerutil.OperationResult, error) {
+func (r *NovaSchedulerReconciler) deploymentCreateOrUpdate(instance *novav1beta1.NovaScheduler, envVars map[string]util.EnvSetter) (controllerutil.OperationResult, error) {
 	runAsUser := int64(0)
 
 	// set KOLLA_CONFIG env vars
 	envVars["KOLLA_CONFIG_FILE"] = util.EnvValue(novascheduler.KollaConfig)
 	envVars["KOLLA_CONFIG_STRATEGY"] = util.EnvValue("COPY_ALWAYS")
 
-	// get readinessProbes
-	readinessProbe := util.Probe{ProbeType: "readiness"}
-	livenessProbe := util.Probe{ProbeType: "liveness"}
-
 	// get volumes
 	initVolumeMounts := common.GetInitVolumeMounts()
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(instance.Spec.ManagingCrName)
 
-	statefulset := &appsv1.StatefulSet{
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(instance.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(instance.Spec.DatabaseTLS)...)
+
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
 			Namespace: instance.Namespace,
 		},
 	}
 
-	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, statefulset, func() error {
+	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, deployment, func() error {
 
-		// statefulset selector is immutable so we set this value only if
+		// Deployment selector is immutable so we set this value only if
 		// a new object is going to be created
-		if statefulset.ObjectMeta.CreationTimestamp.IsZero() {
-			statefulset.Spec.Selector = &metav1.LabelSelector{
+		if deployment.ObjectMeta.CreationTimestamp.IsZero() {
+			deployment.Spec.Selector = &metav1.LabelSelector{
 				MatchLabels: common.GetLabels(instance.Name, novascheduler.AppLabel),
 			}
 		}
 
-		if len(statefulset.Spec.Template.Spec.Containers) != 1 {
-			statefulset.Spec.Template.Spec.Containers = make([]corev1.Container, 1)
+		if len(deployment.Spec.Template.Spec.Containers) != 1 {
+			deployment.Spec.Template.Spec.Containers = make([]corev1.Container, 1)
 		}
-		envs := util.MergeEnvs(statefulset.Spec.Template.Spec.Containers[0].Env, envVars)
+		envs := util.MergeEnvs(deployment.Spec.Template.Spec.Containers[0].Env, envVars)
 
 		// labels
-		common.InitLabelMap(&statefulset.Spec.Template.Labels)
+		common.InitLabelMap(&deployment.Spec.Template.Labels)
 		for k, v := range common.GetLabels(instance.Name, novascheduler.AppLabel) {
-			statefulset.Spec.Template.Labels[k] = v
+			deployment.Spec.Template.Labels[k] = v
 		}
 
-		statefulset.Spec.Replicas = &instance.Spec.Replicas
-		statefulset.Spec.Template.Spec = corev1.PodSpec{
-			ServiceAccountName: serviceAccountName,
-			Volumes:            volumes,
+		// a changed cell mappings hash rolls the pods according to the update strategy
+		common.InitLabelMap(&deployment.Spec.Template.Annotations)
+		deployment.Spec.Template.Annotations[common.CellMappingsHashAnnotation] = instance.Spec.CellMappingsHash
+
+		deployment.Spec.Replicas = &instance.Spec.Replicas
+		// roll the pods within the disruption budget
+		deployment.Spec.Strategy = common.GetRollingUpdateStrategy(instance.Spec.PodDisruptionBudget)
+		deployment.Spec.Template.Spec = corev1.PodSpec{
+			ServiceAccountName:        serviceAccountName,
+			PriorityClassName:         instance.Spec.PriorityClassName,
+			NodeSelector:              instance.Spec.NodeSelector,
+			Tolerations:               instance.Spec.Tolerations,
+			Affinity:                  common.GetPodAffinity(instance.Spec.Affinity, common.GetLabels(instance.Name, novascheduler.AppLabel)),
+			TopologySpreadConstraints: instance.Spec.TopologySpreadConstraints,
+			Volumes:                   volumes,
 			Containers: []corev1.Container{
 				{
-					Name:  "nova-scheduler",
-					Image: instance.Spec.ContainerImage,
+					Name:      "nova-scheduler",
+					Image:     instance.Spec.ContainerImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser: &runAsUser,
 					},
-					ReadinessProbe: readinessProbe.GetProbe(),
-					LivenessProbe:  livenessProbe.GetProbe(),
+					// heartbeat based readiness, the liveness only checks the connection to the message bus
+					StartupProbe:   common.GetHealthCheckStartupProbe("nova-scheduler", instance.Spec.Probes),
+					ReadinessProbe: common.GetHealthCheckReadinessProbe("nova-scheduler", instance.Spec.Probes),
+					LivenessProbe:  common.GetHealthCheckLivenessProbe("nova-scheduler", instance.Spec.Probes),
 					Env:            envs,
 					VolumeMounts:   volumeMounts,
 				},
@@ -245,19 +294,25 @@ func (r *NovaSchedulerReconciler) statefulsetCreateOrUpdate(instance *novav1beta
 		}
 
 		initContainerDetails := common.CtrlInitContainer{
-			ContainerImage:     instance.Spec.ContainerImage,
-			DatabaseHost:       instance.Spec.DatabaseHostname,
-			CellDatabase:       fmt.Sprintf("%s_%s", novascheduler.DatabasePrefix, novascheduler.CellDatabase),
-			APIDatabase:        fmt.Sprintf("%s_%s", novascheduler.DatabasePrefix, novascheduler.APIDatabase),
-			TransportURLSecret: instance.Spec.TransportURLSecret,
-			NovaSecret:         instance.Spec.NovaSecret,
-			NeutronSecret:      instance.Spec.NeutronSecret,
-			PlacementSecret:    instance.Spec.PlacementSecret,
-			VolumeMounts:       initVolumeMounts,
+			ContainerImage:                  instance.Spec.ContainerImage,
+			DatabaseHost:                    instance.Spec.DatabaseHostname,
+			CellDatabase:                    fmt.Sprintf("%s_%s", novascheduler.DatabasePrefix, novascheduler.CellDatabase),
+			APIDatabase:                     fmt.Sprintf("%s_%s", novascheduler.DatabasePrefix, novascheduler.APIDatabase),
+			DatabaseTLS:                     instance.Spec.DatabaseTLS,
+			DatabaseUser:                    instance.Spec.DatabaseUser,
+			TransportURLSecret:              instance.Spec.TransportURLSecret,
+			NotificationsTransportURLSecret: instance.Spec.NotificationsTransportURLSecret,
+			ApplicationCredentialSecret:     instance.Spec.ApplicationCredentialSecret,
+			NovaSecret:                      instance.Spec.NovaSecret,
+			NeutronSecret:                   instance.Spec.NeutronSecret,
+			PlacementSecret:                 instance.Spec.PlacementSecret,
+			PasswordSelectors:               instance.Spec.PasswordSelectors,
+			VolumeMounts:                    initVolumeMounts,
+			Resources:                       instance.Spec.Resources,
 		}
-		statefulset.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
+		deployment.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
 
-		err := controllerutil.SetControllerReference(instance, statefulset, r.Scheme)
+		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
 		if err != nil {
 			return err
 		}
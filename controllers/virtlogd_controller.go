@@ -88,6 +88,16 @@ func (r *VirtlogdReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	// the daemons have to be restricted to the compute nodes
+	err = common.ValidateComputePlacement(instance.Spec.RoleName, instance.Spec.ComputePlacement)
+	if condErr := common.UpdateStatusCondition(r, instance, &instance.Status.Conditions, common.GetPlacementCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	if err != nil {
+		r.Log.Info(err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	// Create/update configmaps from templates
 	cmLabels := common.GetLabels(instance.Name, virtlogd.AppLabel)
 	cmLabels["upper-cr"] = instance.Name
@@ -207,7 +217,7 @@ func (r *VirtlogdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Virtl
 	// tolerations
 	tolerations := []corev1.Toleration{}
 	// add compute worker nodes tolerations
-	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName) {
+	for _, toleration := range common.GetComputeWorkerTolerations(instance.Spec.RoleName, instance.Spec.Tolerations) {
 		tolerations = append(tolerations, toleration)
 	}
 
@@ -241,7 +251,9 @@ func (r *VirtlogdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Virtl
 
 		daemonSet.Spec.Template.Spec = corev1.PodSpec{
 			ServiceAccountName: serviceAccountName,
-			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName),
+			PriorityClassName:  instance.Spec.PriorityClassName,
+			NodeSelector:       common.GetComputeWorkerNodeSelector(instance.Spec.RoleName, instance.Spec.NodeSelector),
+			Affinity:           common.GetComputeWorkerAffinity(instance.Spec.NodeAffinity),
 			HostIPC:            true,
 			HostNetwork:        true,
 			DNSPolicy:          "ClusterFirstWithHostNet",
@@ -249,8 +261,9 @@ func (r *VirtlogdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Virtl
 			Tolerations:        tolerations,
 			InitContainers: []corev1.Container{
 				{
-					Name:  "init",
-					Image: instance.Spec.NovaLibvirtImage,
+					Name:      "init",
+					Image:     instance.Spec.NovaLibvirtImage,
+					Resources: instance.Spec.Resources,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &runAsUser,
 						Privileged: &trueVar,
@@ -266,6 +279,7 @@ func (r *VirtlogdReconciler) daemonsetCreateOrUpdate(instance *novav1beta1.Virtl
 				{
 					Name:           "virtlogd",
 					Image:          instance.Spec.NovaLibvirtImage,
+					Resources:      instance.Spec.Resources,
 					ReadinessProbe: readinessProbe,
 					LivenessProbe:  livenessProbe,
 					SecurityContext: &corev1.SecurityContext{
@@ -141,6 +141,15 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NovaCompute")
 		os.Exit(1)
 	}
+	if err = (&controllers.NovaComputeStackReconciler{
+		Client:  mgr.GetClient(),
+		Kclient: kclient,
+		Log:     ctrl.Log.WithName("controllers").WithName("NovaComputeStack"),
+		Scheme:  mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NovaComputeStack")
+		os.Exit(1)
+	}
 	if err = (&controllers.NovaAPIReconciler{
 		Client:  mgr.GetClient(),
 		Kclient: kclient,
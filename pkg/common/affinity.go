@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NodeAntiAffinityWeight - weight of spreading the replicas across nodes
+	NodeAntiAffinityWeight = 100
+	// ZoneAntiAffinityWeight - weight of spreading the replicas across zones
+	ZoneAntiAffinityWeight = 50
+)
+
+// GetPodAffinity - returns the affinity of the pods of a service, defaults to a soft
+// anti-affinity which prefers to not co-locate replicas on the same node or zone
+func GetPodAffinity(affinity *corev1.Affinity, labels map[string]string) *corev1.Affinity {
+	if affinity != nil {
+		return affinity
+	}
+
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: NodeAntiAffinityWeight,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: labels,
+						},
+						TopologyKey: corev1.LabelHostname,
+					},
+				},
+				{
+					Weight: ZoneAntiAffinityWeight,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: labels,
+						},
+						TopologyKey: corev1.LabelZoneFailureDomainStable,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodAffinity(t *testing.T) {
+	assert := assert.New(t)
+
+	labels := map[string]string{"app": "nova-conductor", "cr": "nova-conductor-cell1"}
+
+	// soft anti-affinity between the replicas across nodes and zones by default
+	affinity := GetPodAffinity(nil, labels)
+	assert.Nil(affinity.NodeAffinity)
+	assert.Nil(affinity.PodAffinity)
+	assert.Empty(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	terms := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(terms, 2)
+	assert.Equal(int32(NodeAntiAffinityWeight), terms[0].Weight)
+	assert.Equal(corev1.LabelHostname, terms[0].PodAffinityTerm.TopologyKey)
+	assert.Equal(int32(ZoneAntiAffinityWeight), terms[1].Weight)
+	assert.Equal(corev1.LabelZoneFailureDomainStable, terms[1].PodAffinityTerm.TopologyKey)
+	for _, term := range terms {
+		assert.Equal(&metav1.LabelSelector{MatchLabels: labels}, term.PodAffinityTerm.LabelSelector)
+	}
+
+	// a user supplied affinity replaces the default, it is not merged
+	nodeAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.NodeSelectorOpExists},
+						},
+					},
+				},
+			},
+		},
+	}
+	affinity = GetPodAffinity(nodeAffinity, labels)
+	assert.Equal(nodeAffinity, affinity)
+	assert.Nil(affinity.PodAntiAffinity)
+
+	// an empty affinity disables the default anti-affinity
+	assert.Equal(&corev1.Affinity{}, GetPodAffinity(&corev1.Affinity{}, labels))
+}
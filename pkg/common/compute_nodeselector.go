@@ -16,10 +16,64 @@ limitations under the License.
 
 package common
 
-// GetComputeWorkerNodeSelector - returns the NodeSelector for all compute worker DS
-func GetComputeWorkerNodeSelector(roleName string) map[string]string {
+import (
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InvalidPlacementError - the compute daemons are not restricted to any nodes
+type InvalidPlacementError struct{}
+
+func (e *InvalidPlacementError) Error() string {
+	return "One of roleName, nodeSelector or nodeAffinity is required to restrict the daemons to the compute nodes"
+}
+
+// ValidateComputePlacement - verify the roleName, nodeSelector or nodeAffinity restrict the compute daemons
+// to some nodes, they would run on all nodes including the control plane otherwise
+func ValidateComputePlacement(roleName string, placement novav1beta1.ComputePlacement) error {
+	if roleName == "" && len(placement.NodeSelector) == 0 && placement.NodeAffinity == nil {
+		return &InvalidPlacementError{}
+	}
+	return nil
+}
+
+// GetPlacementCondition - Placement condition of the validated placement of the compute daemons
+func GetPlacementCondition(err error) novav1beta1.Condition {
+	condition := novav1beta1.Condition{
+		Type:    novav1beta1.PlacementCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  novav1beta1.PlacementValidReason,
+		Message: "Daemons are restricted to the compute nodes",
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = novav1beta1.PlacementInvalidReason
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
+// GetComputeWorkerNodeSelector - returns the NodeSelector for all compute worker DS, the
+// node role label of the roleName merged with the labels of the nodeSelector
+func GetComputeWorkerNodeSelector(roleName string, nodeSelector map[string]string) map[string]string {
+	selector := map[string]string{}
+	for k, v := range nodeSelector {
+		selector[k] = v
+	}
 
 	// Change nodeSelector
-	nodeSelector := "node-role.kubernetes.io/" + roleName
-	return map[string]string{nodeSelector: ""}
+	if roleName != "" {
+		selector["node-role.kubernetes.io/"+roleName] = ""
+	}
+	return selector
+}
+
+// GetComputeWorkerAffinity - returns the Affinity for all compute worker DS, nil without nodeAffinity
+func GetComputeWorkerAffinity(nodeAffinity *corev1.NodeAffinity) *corev1.Affinity {
+	if nodeAffinity == nil {
+		return nil
+	}
+	return &corev1.Affinity{
+		NodeAffinity: nodeAffinity,
+	}
 }
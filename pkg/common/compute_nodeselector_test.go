@@ -0,0 +1,54 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestComputeWorkerNodeSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(map[string]string{"node-role.kubernetes.io/worker-osp": ""}, GetComputeWorkerNodeSelector("worker-osp", nil))
+	assert.Equal(map[string]string{"kubernetes.io/os": "linux"}, GetComputeWorkerNodeSelector("", map[string]string{"kubernetes.io/os": "linux"}))
+	assert.Equal(map[string]string{"node-role.kubernetes.io/worker-osp": "", "kubernetes.io/os": "linux"},
+		GetComputeWorkerNodeSelector("worker-osp", map[string]string{"kubernetes.io/os": "linux"}))
+
+	assert.Len(GetComputeWorkerTolerations("", nil), 0)
+	assert.Len(GetComputeWorkerTolerations("worker-osp", nil), 1)
+	assert.Nil(GetComputeWorkerAffinity(nil))
+}
+
+func TestValidateComputePlacement(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateComputePlacement("worker-osp", novav1beta1.ComputePlacement{}))
+	assert.NoError(ValidateComputePlacement("", novav1beta1.ComputePlacement{NodeSelector: map[string]string{"compute": "true"}}))
+	assert.NoError(ValidateComputePlacement("", novav1beta1.ComputePlacement{NodeAffinity: &corev1.NodeAffinity{}}))
+
+	// tolerations alone do not restrict the nodes
+	err := ValidateComputePlacement("", novav1beta1.ComputePlacement{Tolerations: []corev1.Toleration{{Key: "dedicated"}}})
+	assert.Error(err)
+	condition := GetPlacementCondition(err)
+	assert.Equal(corev1.ConditionFalse, condition.Status)
+	assert.Equal(novav1beta1.PlacementInvalidReason, condition.Reason)
+	assert.Equal(corev1.ConditionTrue, GetPlacementCondition(nil).Status)
+}
@@ -20,16 +20,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// GetComputeWorkerTolerations - common Volumes used by many service pods
-func GetComputeWorkerTolerations(roleName string) []corev1.Toleration {
+// GetComputeWorkerTolerations - returns the Tolerations for all compute worker DS, the
+// dedicated taint of the roleName in addition to the tolerations
+func GetComputeWorkerTolerations(roleName string, tolerations []corev1.Toleration) []corev1.Toleration {
+	workerTolerations := append([]corev1.Toleration{}, tolerations...)
 
-	return []corev1.Toleration{
+	if roleName != "" {
 		// Add toleration
-		{
+		workerTolerations = append(workerTolerations, corev1.Toleration{
 			Operator: "Equal",
 			Effect:   "NoSchedule",
 			Key:      "dedicated",
 			Value:    roleName,
-		},
+		})
 	}
+	return workerTolerations
 }
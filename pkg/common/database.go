@@ -17,18 +17,61 @@ limitations under the License.
 package common
 
 import (
+	"context"
 	"fmt"
 
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
 )
 
-// Database -
+const (
+	// DatabaseCADir - mount path of the DB CA certificate
+	DatabaseCADir = "/etc/pki/nova-db-tls"
+	// DatabaseCAKey - key of the CA certificate in the DB CA secret
+	DatabaseCAKey = "ca.crt"
+	// DatabasePasswordKey - key the MariaDB operator reads the account password from
+	DatabasePasswordKey = "DatabasePassword"
+)
+
+// GetDatabaseConnectionParams - query parameters of the DB connection urls, empty if DB TLS is disabled
+func GetDatabaseConnectionParams(tls novav1beta1.DatabaseTLS) string {
+	switch tls.Mode {
+	case "require":
+		return fmt.Sprintf("?ssl_ca=%s/%s&ssl_check_hostname=false", DatabaseCADir, DatabaseCAKey)
+	case "verify":
+		return fmt.Sprintf("?ssl_ca=%s/%s", DatabaseCADir, DatabaseCAKey)
+	}
+	return ""
+}
+
+// GetDatabaseCASecret - verify the CA secret is set and contains the CA certificate if DB TLS is enabled,
+// returns its hash or an empty string if DB TLS is disabled
+func GetDatabaseCASecret(c client.Client, tls novav1beta1.DatabaseTLS, namespace string) (string, error) {
+	if tls.Mode == "" {
+		return "", nil
+	}
+	if tls.CASecret == "" {
+		return "", &SecretNotSetError{Parameter: "databaseTLS.caSecret", RequiredFor: fmt.Sprintf("databaseTLS.mode %s", tls.Mode)}
+	}
+
+	_, hash, err := GetSecret(c, tls.CASecret, namespace, DatabaseCAKey)
+	return hash, err
+}
+
+// Database - the MariaDBDatabase object creates the database and an account of the same name,
+// using the DatabasePassword of the secret, see GetDatabasePasswordSecret, which is granted only on that database. The account
+// is used by the DB sync jobs, the services connect with their own DatabaseAccount.
 type Database struct {
 	DatabaseHostname string
 	DatabaseName     string
@@ -65,3 +108,108 @@ func DatabaseObject(r ReconcilerCommon, obj metav1.Object, db Database) (unstruc
 
 	return u, err
 }
+
+// GetDatabaseSecret - NovaSecret of the accounts of the databases, used by the DB sync jobs. During a DB
+// password rotation it is the previous NovaSecret until all services rolled out with the new one.
+func GetDatabaseSecret(secret string, previousSecret string) string {
+	if previousSecret != "" {
+		return previousSecret
+	}
+	return secret
+}
+
+// GetDatabasePasswordSecret - secret the MariaDB objects read the DatabasePassword from, the NovaSecret itself
+// or, with a custom database password selector, a secret of the owner holding a copy of the selected key
+func GetDatabasePasswordSecret(obj metav1.Object, secret string, selectors novav1beta1.PasswordSelector) string {
+	if GetPasswordSelectors(selectors).Database == DatabasePasswordKey {
+		return secret
+	}
+	return fmt.Sprintf("%s-%s-db", obj.GetName(), secret)
+}
+
+// EnsureDatabasePasswordSecret - create or update the secret the MariaDB objects read the DatabasePassword from,
+// returns its name
+func EnsureDatabasePasswordSecret(r ReconcilerCommon, obj metav1.Object, secret string, selectors novav1beta1.PasswordSelector) (string, error) {
+	name := GetDatabasePasswordSecret(obj, secret, selectors)
+	if name == secret {
+		return secret, nil
+	}
+
+	novaSecret, _, err := GetSecret(r.GetClient(), secret, obj.GetNamespace(), GetPasswordSelectors(selectors).Database)
+	if err != nil {
+		return "", err
+	}
+
+	dbSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.GetClient(), dbSecret, func() error {
+		dbSecret.Type = corev1.SecretTypeOpaque
+		dbSecret.Data = map[string][]byte{
+			DatabasePasswordKey: novaSecret.Data[GetPasswordSelectors(selectors).Database],
+		}
+		return controllerutil.SetControllerReference(obj, dbSecret, r.GetScheme())
+	})
+	if err != nil {
+		return "", err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.GetLogger().Info(fmt.Sprintf("DB password secret %s successfully reconciled - operation: %s", name, string(op)))
+	}
+	return name, nil
+}
+
+// DeleteDatabasePasswordSecret - delete the copy of the DatabasePassword of the secret, if one got created
+func DeleteDatabasePasswordSecret(r ReconcilerCommon, obj metav1.Object, secret string, selectors novav1beta1.PasswordSelector) error {
+	name := GetDatabasePasswordSecret(obj, secret, selectors)
+	if name == secret {
+		return nil
+	}
+
+	err := r.GetClient().Delete(context.TODO(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: obj.GetNamespace()}})
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// EnsureDatabaseSecret - point the existing DB object to the secret of the database, the DB operator then
+// updates the account password. Returns true if the DB object got updated.
+func EnsureDatabaseSecret(r ReconcilerCommon, obj metav1.Object, db Database) (bool, error) {
+	databaseObj, err := DatabaseObject(r, obj, db)
+	if err != nil {
+		return false, err
+	}
+
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(databaseObj.GroupVersionKind())
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: databaseObj.GetName(), Namespace: databaseObj.GetNamespace()}, found)
+	if err != nil && k8s_errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	currentSecret, _, err := unstructured.NestedString(found.UnstructuredContent(), "spec", "secret")
+	if err != nil {
+		return false, err
+	}
+	if currentSecret == db.Secret {
+		return false, nil
+	}
+
+	err = unstructured.SetNestedField(found.Object, db.Secret, "spec", "secret")
+	if err != nil {
+		return false, err
+	}
+
+	r.GetLogger().Info(fmt.Sprintf("Updating %s DB account to secret %s", found.GetName(), db.Secret))
+	err = r.GetClient().Update(context.TODO(), found)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDatabaseSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	// the DB sync jobs use the previous NovaSecret until the rotation completed
+	assert.Equal("nova-secret", GetDatabaseSecret("nova-secret", ""))
+	assert.Equal("nova-secret", GetDatabaseSecret("nova-secret-v2", "nova-secret"))
+}
+
+func TestGetDatabaseConnectionParams(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", GetDatabaseConnectionParams(novav1beta1.DatabaseTLS{}))
+	assert.Equal("?ssl_ca=/etc/pki/nova-db-tls/ca.crt&ssl_check_hostname=false",
+		GetDatabaseConnectionParams(novav1beta1.DatabaseTLS{Mode: "require", CASecret: "db-ca"}))
+	assert.Equal("?ssl_ca=/etc/pki/nova-db-tls/ca.crt",
+		GetDatabaseConnectionParams(novav1beta1.DatabaseTLS{Mode: "verify", CASecret: "db-ca"}))
+}
+
+func TestGetDatabaseCASecret(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newTestReconciler(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-ca", Namespace: "openstack"},
+		Data:       map[string][]byte{"ca.crt": []byte("cert")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-ca-invalid", Namespace: "openstack"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert")},
+	})
+
+	// DB TLS disabled
+	hash, err := GetDatabaseCASecret(r.GetClient(), novav1beta1.DatabaseTLS{}, "openstack")
+	assert.NoError(err)
+	assert.Empty(hash)
+
+	hash, err = GetDatabaseCASecret(r.GetClient(), novav1beta1.DatabaseTLS{Mode: "verify", CASecret: "db-ca"}, "openstack")
+	assert.NoError(err)
+	assert.NotEmpty(hash)
+
+	// the CA secret is required with a TLS mode, otherwise the volume would have no secret
+	_, err = GetDatabaseCASecret(r.GetClient(), novav1beta1.DatabaseTLS{Mode: "require"}, "openstack")
+	assert.True(IsSecretInvalid(err))
+	assert.Equal("databaseTLS.caSecret is required for databaseTLS.mode require", err.Error())
+	assert.Equal(novav1beta1.SecretNotSetReason, GetSecretCondition(err).Reason)
+
+	_, err = GetDatabaseCASecret(r.GetClient(), novav1beta1.DatabaseTLS{Mode: "verify", CASecret: "db-ca-invalid"}, "openstack")
+	assert.True(IsSecretMissingKey(err))
+}
+
+func TestEnsureDatabasePasswordSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaCell{ObjectMeta: metav1.ObjectMeta{Name: "nova-cell1", Namespace: "openstack"}}
+	r := newTestReconciler(instance, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-secret", Namespace: "openstack"},
+		Data: map[string][]byte{
+			"DatabasePassword": []byte("foo"),
+			"NovaDBPassword":   []byte("bar"),
+		},
+	})
+
+	// the default key is read from the NovaSecret itself
+	name, err := EnsureDatabasePasswordSecret(r, instance, "nova-secret", novav1beta1.PasswordSelector{})
+	assert.NoError(err)
+	assert.Equal("nova-secret", name)
+
+	// a custom key gets copied to the DatabasePassword key
+	selectors := novav1beta1.PasswordSelector{Database: "NovaDBPassword"}
+	name, err = EnsureDatabasePasswordSecret(r, instance, "nova-secret", selectors)
+	assert.NoError(err)
+	assert.Equal("nova-cell1-nova-secret-db", name)
+	assert.Equal(name, GetDatabasePasswordSecret(instance, "nova-secret", selectors))
+
+	secret, _, err := GetSecret(r.GetClient(), name, "openstack", DatabasePasswordKey)
+	assert.NoError(err)
+	assert.Equal([]byte("bar"), secret.Data[DatabasePasswordKey])
+	assert.True(metav1.IsControlledBy(secret, instance))
+
+	// a missing custom key is reported
+	_, err = EnsureDatabasePasswordSecret(r, instance, "nova-secret", novav1beta1.PasswordSelector{Database: "unknown"})
+	assert.True(IsSecretMissingKey(err))
+
+	assert.NoError(DeleteDatabasePasswordSecret(r, instance, "nova-secret", selectors))
+	_, _, err = GetSecret(r.GetClient(), name, "openstack")
+	assert.Error(err)
+	// the NovaSecret itself is never deleted
+	assert.NoError(DeleteDatabasePasswordSecret(r, instance, "nova-secret", novav1beta1.PasswordSelector{}))
+	_, _, err = GetSecret(r.GetClient(), "nova-secret", "openstack")
+	assert.NoError(err)
+}
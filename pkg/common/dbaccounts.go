@@ -0,0 +1,221 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DatabaseAccountsAppLabel - app label of the DB accounts job
+const DatabaseAccountsAppLabel = "nova-db-accounts"
+
+// DatabaseAccount - account of a service on a DB server, created by the DB accounts job with the password
+// of the NovaSecret and granted only the listed databases. The cell mappings use the credentials of the
+// reading service, so services reading the cells need their databases. Each NovaSecret gets its own
+// accounts, during a DB password rotation the accounts of the previous NovaSecret stay valid until all
+// services rolled out with the new one.
+type DatabaseAccount struct {
+	DatabaseHostname string
+	UserName         string
+	Databases        []string
+	Secret           string
+}
+
+// DatabaseServer - DB server the DB accounts job connects to as root, with the password of the admin secret
+type DatabaseServer struct {
+	Hostname    string
+	AdminSecret string
+}
+
+// DatabaseAccountsJobDetails - details of the DB accounts job of a CR
+type DatabaseAccountsJobDetails struct {
+	Name           string
+	ContainerImage string
+	// accounts with the prefix which are not listed get dropped, e.g. the ones of a previous NovaSecret
+	UserPrefix        string
+	Servers           []DatabaseServer
+	Accounts          []DatabaseAccount
+	PasswordSelectors novav1beta1.PasswordSelector
+	DatabaseTLS       novav1beta1.DatabaseTLS
+	JobRetryLimit     int32
+	PriorityClassName string
+	Resources         corev1.ResourceRequirements
+}
+
+// databaseAccountsServer - DB server and its accounts passed to the DB accounts script,
+// the passwords are read from the named env vars
+type databaseAccountsServer struct {
+	Host          string                    `json:"host"`
+	AdminPassword string                    `json:"adminPassword"`
+	Accounts      []databaseAccountsAccount `json:"accounts"`
+}
+
+type databaseAccountsAccount struct {
+	User      string   `json:"user"`
+	Password  string   `json:"password"`
+	Databases []string `json:"databases"`
+}
+
+// GetDatabaseUser - DB account name of a service of the api level or of a cell for the NovaSecret,
+// e.g. nova_cell1_conductor_1a2b3c4d
+func GetDatabaseUser(level string, service string, secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("nova_%s_%s_%s", level, service, hex.EncodeToString(hash[:4]))
+}
+
+// GetDatabaseUserPrefix - prefix of the DB account names of the api level or of a cell
+func GetDatabaseUserPrefix(level string) string {
+	return fmt.Sprintf("nova_%s_", level)
+}
+
+// GetDatabaseAdminSecret - verify the admin secret of the DB server is set and contains the root password
+func GetDatabaseAdminSecret(c client.Client, server DatabaseServer, namespace string, selectors novav1beta1.PasswordSelector) error {
+	if server.AdminSecret == "" {
+		return &SecretNotSetError{Parameter: "databaseAdminSecret", RequiredFor: fmt.Sprintf("the DB accounts on %s", server.Hostname)}
+	}
+
+	_, _, err := GetSecret(c, server.AdminSecret, namespace, GetPasswordSelectors(selectors).DatabaseAdmin)
+	return err
+}
+
+// DatabaseAccountsJob - job creating the DB accounts of the services on the DB servers and granting them their
+// databases. It is idempotent and drops the accounts with the user prefix which are not listed, so it gets
+// run again whenever the accounts change.
+func DatabaseAccountsJob(obj metav1.Object, scheme *runtime.Scheme, details DatabaseAccountsJobDetails) (*batchv1.Job, error) {
+	selectors := GetPasswordSelectors(details.PasswordSelectors)
+
+	runAsUser := int64(0)
+
+	volumeMounts := GetVolumeMounts()
+	volumes := GetVolumes(details.Name)
+
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, GetDatabaseTLSVolumeMounts(details.DatabaseTLS)...)
+	volumes = append(volumes, GetDatabaseTLSVolumes(details.DatabaseTLS)...)
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "DatabaseUserPrefix",
+			Value: details.UserPrefix,
+		},
+		{
+			Name:  "DatabaseTLSMode",
+			Value: details.DatabaseTLS.Mode,
+		},
+		{
+			Name:  "DatabaseCA",
+			Value: fmt.Sprintf("%s/%s", DatabaseCADir, DatabaseCAKey),
+		},
+	}
+
+	// the passwords only get referenced, one env var per secret
+	secretEnv := map[string]string{}
+	getPasswordEnv := func(prefix string, secret string, key string) string {
+		if name, ok := secretEnv[prefix+"/"+secret]; ok {
+			return name
+		}
+		name := fmt.Sprintf("%s%d", prefix, len(secretEnv))
+		secretEnv[prefix+"/"+secret] = name
+		env = append(env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secret,
+					},
+					Key: key,
+				},
+			},
+		})
+		return name
+	}
+
+	servers := []databaseAccountsServer{}
+	for _, server := range details.Servers {
+		accounts := []databaseAccountsAccount{}
+		for _, account := range details.Accounts {
+			if account.DatabaseHostname != server.Hostname {
+				continue
+			}
+			accounts = append(accounts, databaseAccountsAccount{
+				User:      account.UserName,
+				Password:  getPasswordEnv("DatabasePassword", account.Secret, selectors.Database),
+				Databases: account.Databases,
+			})
+		}
+		servers = append(servers, databaseAccountsServer{
+			Host:          server.Hostname,
+			AdminPassword: getPasswordEnv("DatabaseAdminPassword", server.AdminSecret, selectors.DatabaseAdmin),
+			Accounts:      accounts,
+		})
+	}
+	serversJSON, err := json.Marshal(servers)
+	if err != nil {
+		return nil, err
+	}
+	env = append(env, corev1.EnvVar{
+		Name:  "DatabaseServers",
+		Value: string(serversJSON),
+	})
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      details.Name + "-db-accounts",
+			Namespace: obj.GetNamespace(),
+			Labels:    GetLabels(details.Name, DatabaseAccountsAppLabel),
+		},
+		Spec: batchv1.JobSpec{
+			// keep failed pods and their logs, kubernetes retries with an exponential back-off
+			BackoffLimit: GetJobRetryLimit(details.JobRetryLimit),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "Never",
+					ServiceAccountName: "nova",
+					PriorityClassName:  details.PriorityClassName,
+					Volumes:            volumes,
+					Containers: []corev1.Container{
+						{
+							Name:      details.Name + "-db-accounts",
+							Image:     details.ContainerImage,
+							Command:   []string{"/usr/local/bin/container-scripts/db-accounts.sh"},
+							Resources: details.Resources,
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser: &runAsUser,
+							},
+							Env:          env,
+							VolumeMounts: volumeMounts,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err = controllerutil.SetControllerReference(obj, job, scheme)
+	return job, err
+}
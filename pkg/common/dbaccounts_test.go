@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDatabaseUser(t *testing.T) {
+	assert := assert.New(t)
+
+	// each NovaSecret gets its own accounts
+	user := GetDatabaseUser("cell1", "conductor", "nova-secret")
+	assert.Regexp("^nova_cell1_conductor_[0-9a-f]{8}$", user)
+	assert.Equal(user, GetDatabaseUser("cell1", "conductor", "nova-secret"))
+	assert.NotEqual(user, GetDatabaseUser("cell1", "conductor", "nova-secret-v2"))
+	assert.NotEqual(user, GetDatabaseUser("cell1", "metadata", "nova-secret"))
+	assert.Regexp("^"+GetDatabaseUserPrefix("cell1"), user)
+}
+
+func TestGetDatabaseAdminSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newTestReconciler(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-secret", Namespace: "openstack"},
+		Data:       map[string][]byte{"DbRootPassword": []byte("foo")},
+	})
+
+	server := DatabaseServer{Hostname: "openstack-db", AdminSecret: "db-secret"}
+	assert.NoError(GetDatabaseAdminSecret(r.GetClient(), server, "openstack", novav1beta1.PasswordSelector{}))
+
+	err := GetDatabaseAdminSecret(r.GetClient(), server, "openstack", novav1beta1.PasswordSelector{DatabaseAdmin: "RootPassword"})
+	assert.True(IsSecretMissingKey(err))
+
+	err = GetDatabaseAdminSecret(r.GetClient(), DatabaseServer{Hostname: "openstack-db"}, "openstack", novav1beta1.PasswordSelector{})
+	assert.True(IsSecretInvalid(err))
+	assert.Equal("databaseAdminSecret is required for the DB accounts on openstack-db", err.Error())
+}
+
+func TestDatabaseAccountsJob(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.Nova{ObjectMeta: metav1.ObjectMeta{Name: "nova", Namespace: "openstack"}}
+	r := newTestReconciler(instance)
+
+	details := DatabaseAccountsJobDetails{
+		Name:           "nova",
+		ContainerImage: "nova-api:latest",
+		UserPrefix:     GetDatabaseUserPrefix("api"),
+		Servers: []DatabaseServer{
+			{Hostname: "openstack-db", AdminSecret: "db-secret"},
+			{Hostname: "cell1-db", AdminSecret: "cell1-db-secret"},
+		},
+		Accounts: []DatabaseAccount{
+			{DatabaseHostname: "openstack-db", UserName: "nova_api_api_1", Databases: []string{"nova_api", "nova_cell0"}, Secret: "nova-secret-v2"},
+			{DatabaseHostname: "cell1-db", UserName: "nova_api_api_1", Databases: []string{"nova_cell1"}, Secret: "nova-secret-v2"},
+			// previous NovaSecret during a DB password rotation
+			{DatabaseHostname: "openstack-db", UserName: "nova_api_api_0", Databases: []string{"nova_api", "nova_cell0"}, Secret: "nova-secret"},
+		},
+		PasswordSelectors: novav1beta1.PasswordSelector{Database: "NovaDBPassword"},
+		PriorityClassName: "high",
+		JobRetryLimit:     3,
+	}
+	job, err := DatabaseAccountsJob(instance, r.GetScheme(), details)
+	assert.NoError(err)
+	assert.Equal("nova-db-accounts", job.Name)
+	assert.Equal("openstack", job.Namespace)
+	assert.True(metav1.IsControlledBy(job, instance))
+	assert.Equal(int32(3), *job.Spec.BackoffLimit)
+	assert.Equal("high", job.Spec.Template.Spec.PriorityClassName)
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal("nova-api:latest", container.Image)
+	env := map[string]corev1.EnvVar{}
+	for _, e := range container.Env {
+		env[e.Name] = e
+	}
+	assert.Equal("nova_api_", env["DatabaseUserPrefix"].Value)
+
+	// the passwords only get referenced, with the selected keys and one env var per secret
+	refs := map[string]string{}
+	for name, e := range env {
+		if e.ValueFrom != nil {
+			refs[name] = e.ValueFrom.SecretKeyRef.Name + "/" + e.ValueFrom.SecretKeyRef.Key
+		}
+	}
+	assert.Len(refs, 4)
+
+	servers := []databaseAccountsServer{}
+	assert.NoError(json.Unmarshal([]byte(env["DatabaseServers"].Value), &servers))
+	assert.Len(servers, 2)
+	assert.Equal("openstack-db", servers[0].Host)
+	assert.Equal("db-secret/DbRootPassword", refs[servers[0].AdminPassword])
+	assert.Len(servers[0].Accounts, 2)
+	assert.Equal("nova_api_api_1", servers[0].Accounts[0].User)
+	assert.Equal([]string{"nova_api", "nova_cell0"}, servers[0].Accounts[0].Databases)
+	assert.Equal("nova-secret-v2/NovaDBPassword", refs[servers[0].Accounts[0].Password])
+	assert.Equal("nova-secret/NovaDBPassword", refs[servers[0].Accounts[1].Password])
+
+	assert.Equal("cell1-db", servers[1].Host)
+	assert.Equal("cell1-db-secret/DbRootPassword", refs[servers[1].AdminPassword])
+	assert.Len(servers[1].Accounts, 1)
+	assert.Equal([]string{"nova_cell1"}, servers[1].Accounts[0].Databases)
+	assert.Equal(servers[0].Accounts[0].Password, servers[1].Accounts[0].Password)
+}
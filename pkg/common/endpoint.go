@@ -0,0 +1,325 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// KeystoneAuthURL - keystone endpoint the services authenticate against
+	KeystoneAuthURL = "http://keystone.openstack.svc:5000/v3"
+	// EndpointCheckTimeout - timeout of a single request of the API endpoint check
+	EndpointCheckTimeout = 10 * time.Second
+)
+
+// HTTPClient - client of the API endpoint check, an interface to test against a stand-in
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// GetDefaultHTTPClient - HTTP client of the API endpoint check if none got injected
+func GetDefaultHTTPClient() HTTPClient {
+	return &http.Client{Timeout: EndpointCheckTimeout}
+}
+
+// KeystoneCredentials - service user, or application credential if its ID is set, to get a token for the
+// authenticated API endpoint check
+type KeystoneCredentials struct {
+	AuthURL                     string
+	Username                    string
+	Password                    string
+	Project                     string
+	ApplicationCredentialID     string
+	ApplicationCredentialSecret string
+}
+
+// GetKeystoneCredentials - credentials of the nova service user, or of the application credential the
+// services authenticate with if the secret is set
+func GetKeystoneCredentials(authURL string, password string, applicationCredentialSecret *corev1.Secret, selectors novav1beta1.PasswordSelector) KeystoneCredentials {
+	credentials := KeystoneCredentials{
+		AuthURL:  GetKeystoneAuthURL(authURL),
+		Username: "nova",
+		Password: password,
+		Project:  "service",
+	}
+	if applicationCredentialSecret != nil {
+		selectors = GetPasswordSelectors(selectors)
+		credentials.ApplicationCredentialID = string(applicationCredentialSecret.Data[selectors.ApplicationCredentialID])
+		credentials.ApplicationCredentialSecret = string(applicationCredentialSecret.Data[selectors.ApplicationCredentialSecret])
+	}
+	return credentials
+}
+
+// GetKeystoneAuthURL - the keystone endpoint, or the default if not set
+func GetKeystoneAuthURL(authURL string) string {
+	if authURL == "" {
+		return KeystoneAuthURL
+	}
+	return authURL
+}
+
+// versionDocument - version discovery document of a versioned API endpoint
+type versionDocument struct {
+	Version struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"version"`
+}
+
+// doRequest - send the request and fail on a non 2xx status code
+func doRequest(client HTTPClient, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s returned %s", req.Method, req.URL, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// CheckVersionDocument - GET the version document of the endpoint and verify it reports a version
+func CheckVersionDocument(client HTTPClient, endpoint string) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := doRequest(client, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	doc := versionDocument{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("invalid version document of %s: %v", endpoint, err)
+	}
+	if doc.Version.ID == "" {
+		return fmt.Errorf("version document of %s reports no version", endpoint)
+	}
+
+	return nil
+}
+
+// getKeystoneAuth - password auth request of the project scoped token, or application credential auth
+// request if set, the token of which is scoped to the project of the application credential
+func getKeystoneAuth(credentials KeystoneCredentials) map[string]interface{} {
+	if credentials.ApplicationCredentialID != "" {
+		return map[string]interface{}{
+			"auth": map[string]interface{}{
+				"identity": map[string]interface{}{
+					"methods": []string{"application_credential"},
+					"application_credential": map[string]string{
+						"id":     credentials.ApplicationCredentialID,
+						"secret": credentials.ApplicationCredentialSecret,
+					},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     credentials.Username,
+						"domain":   map[string]string{"name": "Default"},
+						"password": credentials.Password,
+					},
+				},
+			},
+			"scope": map[string]interface{}{
+				"project": map[string]interface{}{
+					"name":   credentials.Project,
+					"domain": map[string]string{"name": "Default"},
+				},
+			},
+		},
+	}
+}
+
+// keystoneTokenBody - body of the token response, the service catalog of the project of the token
+type keystoneTokenBody struct {
+	Token struct {
+		Catalog []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// KeystoneToken - project scoped token and the service catalog of its project
+type KeystoneToken struct {
+	ID string
+	// URLs of the endpoints by service type and interface
+	endpoints map[string]map[string]string
+}
+
+// GetEndpoint - URL of the endpoint of the service type with the interface, e.g. internal, in the catalog
+func (t *KeystoneToken) GetEndpoint(serviceType string, endpointInterface string) (string, error) {
+	url := t.endpoints[serviceType][endpointInterface]
+	if url == "" {
+		return "", fmt.Errorf("no %s endpoint of the %s service in the catalog", endpointInterface, serviceType)
+	}
+	return url, nil
+}
+
+// IssueKeystoneToken - project scoped token of the credentials with the service catalog
+func IssueKeystoneToken(client HTTPClient, credentials KeystoneCredentials) (*KeystoneToken, error) {
+	body, err := json.Marshal(getKeystoneAuth(credentials))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(credentials.AuthURL, "/")+"/auth/tokens", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := doRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	token := &KeystoneToken{
+		ID:        resp.Header.Get("X-Subject-Token"),
+		endpoints: map[string]map[string]string{},
+	}
+	if token.ID == "" {
+		return nil, fmt.Errorf("no token in the response of %s", req.URL)
+	}
+
+	tokenBody := keystoneTokenBody{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenBody); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid token response of %s: %v", req.URL, err)
+	}
+	for _, service := range tokenBody.Token.Catalog {
+		if token.endpoints[service.Type] == nil {
+			token.endpoints[service.Type] = map[string]string{}
+		}
+		for _, endpoint := range service.Endpoints {
+			token.endpoints[service.Type][endpoint.Interface] = endpoint.URL
+		}
+	}
+
+	return token, nil
+}
+
+// GetKeystoneToken - project scoped token of the credentials
+func GetKeystoneToken(client HTTPClient, credentials KeystoneCredentials) (string, error) {
+	token, err := IssueKeystoneToken(client, credentials)
+	if err != nil {
+		return "", err
+	}
+	return token.ID, nil
+}
+
+// NovaService - record of a nova service in the os-services API
+type NovaService struct {
+	Binary string `json:"binary"`
+	Host   string `json:"host"`
+	// enabled or disabled
+	Status         string `json:"status"`
+	DisabledReason string `json:"disabled_reason"`
+	// up or down, derived from the heartbeat of the service by the API
+	State string `json:"state"`
+}
+
+// GetServices - authenticated GET /os-services of the endpoint, filtered by the binary if set
+func GetServices(client HTTPClient, endpoint string, token string, binary string) ([]NovaService, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/os-services", nil)
+	if err != nil {
+		return nil, err
+	}
+	if binary != "" {
+		query := req.URL.Query()
+		query.Set("binary", binary)
+		req.URL.RawQuery = query.Encode()
+	}
+	req.Header.Set("X-Auth-Token", token)
+	resp, err := doRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	services := struct {
+		Services []NovaService `json:"services"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, fmt.Errorf("invalid services of %s: %v", req.URL, err)
+	}
+
+	return services.Services, nil
+}
+
+// CheckServices - authenticated GET /os-services of the endpoint
+func CheckServices(client HTTPClient, endpoint string, token string) error {
+	_, err := GetServices(client, endpoint, token, "")
+	return err
+}
+
+// CheckAPIEndpoint - verify the version document of the endpoint and, with credentials, an authenticated
+// GET /os-services
+func CheckAPIEndpoint(client HTTPClient, endpoint string, credentials *KeystoneCredentials) error {
+	if err := CheckVersionDocument(client, endpoint); err != nil {
+		return err
+	}
+	if credentials == nil {
+		return nil
+	}
+
+	token, err := GetKeystoneToken(client, *credentials)
+	if err != nil {
+		return err
+	}
+	return CheckServices(client, endpoint, token)
+}
+
+// GetAPIEndpointCondition - APIEndpoint condition of the result of the API endpoint check
+func GetAPIEndpointCondition(err error) novav1beta1.Condition {
+	condition := novav1beta1.Condition{
+		Type:    novav1beta1.APIEndpointCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  novav1beta1.APIEndpointReadyReason,
+		Message: "API endpoint responds",
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = novav1beta1.APIEndpointUnavailableReason
+		condition.Message = err.Error()
+	}
+	return condition
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// newAPIStandIn - stand-in of the keystone and nova APIs, nova returns apiStatus on /v2.1
+func newAPIStandIn(apiStatus int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		auth := struct {
+			Auth struct {
+				Identity struct {
+					Methods               []string          `json:"methods"`
+					ApplicationCredential map[string]string `json:"application_credential"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&auth)
+		identity := auth.Auth.Identity
+		if len(identity.Methods) != 1 ||
+			(identity.Methods[0] == "application_credential" && identity.ApplicationCredential["secret"] != "app-secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-Subject-Token", "token")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": {"catalog": [{"type": "compute", "endpoints": [
+			{"interface": "public", "url": "https://nova.example.com/v2.1"},
+			{"interface": "internal", "url": "http://%s/v2.1"}]}]}}`, r.Host)
+	})
+	mux.HandleFunc("/v2.1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(apiStatus)
+		fmt.Fprint(w, `{"version": {"id": "v2.1", "status": "CURRENT"}}`)
+	})
+	mux.HandleFunc("/v2.1/os-services", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		services := map[string]string{
+			"nova-conductor": `{"binary": "nova-conductor", "host": "nova-conductor-0", "status": "enabled", "state": "up"}`,
+			"nova-compute":   `{"binary": "nova-compute", "host": "worker-0", "status": "disabled", "state": "down", "disabled_reason": "maintenance"}`,
+		}
+		binary := r.URL.Query().Get("binary")
+		if binary != "" {
+			fmt.Fprintf(w, `{"services": [%s]}`, services[binary])
+			return
+		}
+		fmt.Fprintf(w, `{"services": [%s, %s]}`, services["nova-conductor"], services["nova-compute"])
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestCheckAPIEndpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	server := newAPIStandIn(http.StatusOK)
+	defer server.Close()
+	credentials := &KeystoneCredentials{
+		AuthURL:  server.URL + "/v3",
+		Username: "nova",
+		Password: "password",
+		Project:  "service",
+	}
+
+	assert.NoError(CheckAPIEndpoint(server.Client(), server.URL+"/v2.1", nil))
+	assert.NoError(CheckAPIEndpoint(server.Client(), server.URL+"/v2.1", credentials))
+	assert.Error(CheckServices(server.Client(), server.URL+"/v2.1", "invalid"))
+
+	// the services authenticate with the application credential
+	credentials.ApplicationCredentialID = "app-id"
+	credentials.ApplicationCredentialSecret = "app-secret"
+	assert.NoError(CheckAPIEndpoint(server.Client(), server.URL+"/v2.1", credentials))
+	credentials.ApplicationCredentialSecret = "invalid"
+	assert.Error(CheckAPIEndpoint(server.Client(), server.URL+"/v2.1", credentials))
+	// no version document
+	assert.Error(CheckAPIEndpoint(server.Client(), server.URL+"/", nil))
+}
+
+func TestCheckAPIEndpointUnavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	server := newAPIStandIn(http.StatusServiceUnavailable)
+	defer server.Close()
+
+	err := CheckAPIEndpoint(server.Client(), server.URL+"/v2.1", nil)
+	assert.Error(err)
+	condition := GetAPIEndpointCondition(err)
+	assert.Equal(corev1.ConditionFalse, condition.Status)
+	assert.Equal(novav1beta1.APIEndpointUnavailableReason, condition.Reason)
+
+	// endpoint down
+	server.Close()
+	assert.Error(CheckAPIEndpoint(server.Client(), server.URL+"/v2.1", nil))
+}
+
+func TestGetKeystoneAuthURL(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("http://keystone.openstack.svc:5000/v3", GetKeystoneAuthURL(""))
+	assert.Equal("https://keystone.example.com:5000/v3", GetKeystoneAuthURL("https://keystone.example.com:5000/v3"))
+}
+
+func TestGetServices(t *testing.T) {
+	assert := assert.New(t)
+
+	server := newAPIStandIn(http.StatusOK)
+	defer server.Close()
+	credentials := KeystoneCredentials{
+		AuthURL:  server.URL + "/v3",
+		Username: "nova",
+		Password: "password",
+		Project:  "service",
+	}
+
+	// the internal endpoint of the catalog of the token
+	token, err := IssueKeystoneToken(server.Client(), credentials)
+	assert.NoError(err)
+	assert.Equal("token", token.ID)
+	endpoint, err := token.GetEndpoint("compute", "internal")
+	assert.NoError(err)
+	assert.Equal(server.URL+"/v2.1", endpoint)
+	_, err = token.GetEndpoint("compute", "admin")
+	assert.Error(err)
+	_, err = token.GetEndpoint("placement", "internal")
+	assert.Error(err)
+
+	services, err := GetServices(server.Client(), endpoint, token.ID, "nova-compute")
+	assert.NoError(err)
+	assert.Equal([]NovaService{
+		{Binary: "nova-compute", Host: "worker-0", Status: "disabled", DisabledReason: "maintenance", State: "down"},
+	}, services)
+
+	services, err = GetServices(server.Client(), endpoint, token.ID, "")
+	assert.NoError(err)
+	assert.Len(services, 2)
+
+	_, err = GetServices(server.Client(), endpoint, "invalid", "nova-compute")
+	assert.Error(err)
+}
+
+func TestGetKeystoneCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	// the nova service user
+	assert.Equal(KeystoneCredentials{
+		AuthURL:  KeystoneAuthURL,
+		Username: "nova",
+		Password: "password",
+		Project:  "service",
+	}, GetKeystoneCredentials("", "password", nil, novav1beta1.PasswordSelector{}))
+
+	// the application credential of the selectors
+	secret := &corev1.Secret{Data: map[string][]byte{"AppCredID": []byte("app-id"), "AppCredSecret": []byte("app-secret")}}
+	credentials := GetKeystoneCredentials("https://keystone.example.com:5000/v3", "password", secret,
+		novav1beta1.PasswordSelector{ApplicationCredentialID: "AppCredID", ApplicationCredentialSecret: "AppCredSecret"})
+	assert.Equal("https://keystone.example.com:5000/v3", credentials.AuthURL)
+	assert.Equal("app-id", credentials.ApplicationCredentialID)
+	assert.Equal("app-secret", credentials.ApplicationCredentialSecret)
+}
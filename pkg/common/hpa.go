@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DefaultTargetCPUUtilizationPercentage - CPU utilization target of the HorizontalPodAutoscaler without metrics
+const DefaultTargetCPUUtilizationPercentage = int32(80)
+
+// GetMinReplicas - the lowest number of replicas of a service, which is the MinReplicas of
+// an enabled autoscaling or the replicas otherwise
+func GetMinReplicas(replicas int32, autoscaling novav1beta1.Autoscaling) int32 {
+	if autoscaling.Enabled && autoscaling.MinReplicas != nil {
+		return *autoscaling.MinReplicas
+	}
+	return replicas
+}
+
+// EnsureHorizontalPodAutoscaler - creates or updates the HorizontalPodAutoscaler of the Deployment
+// with the name of the object if the autoscaling is enabled, deletes it otherwise
+func EnsureHorizontalPodAutoscaler(r ReconcilerCommon, obj metav1.Object, replicas int32, autoscaling novav1beta1.Autoscaling) (controllerutil.OperationResult, error) {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+
+	if !autoscaling.Enabled {
+		err := r.GetClient().Delete(context.TODO(), hpa)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return controllerutil.OperationResultNone, err
+		}
+		return controllerutil.OperationResultNone, nil
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.GetClient(), hpa, func() error {
+		minReplicas := GetMinReplicas(replicas, autoscaling)
+		maxReplicas := autoscaling.MaxReplicas
+		if maxReplicas < minReplicas {
+			maxReplicas = minReplicas
+		}
+
+		metrics := append([]autoscalingv2beta2.MetricSpec{}, autoscaling.Metrics...)
+		targetCPU := autoscaling.TargetCPUUtilizationPercentage
+		if targetCPU == nil && len(metrics) == 0 {
+			defaultTargetCPU := DefaultTargetCPUUtilizationPercentage
+			targetCPU = &defaultTargetCPU
+		}
+		if targetCPU != nil {
+			metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+				Type: autoscalingv2beta2.ResourceMetricSourceType,
+				Resource: &autoscalingv2beta2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2beta2.MetricTarget{
+						Type:               autoscalingv2beta2.UtilizationMetricType,
+						AverageUtilization: targetCPU,
+					},
+				},
+			})
+		}
+
+		hpa.Spec = autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       obj.GetName(),
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		}
+
+		return controllerutil.SetControllerReference(obj, hpa, r.GetScheme())
+	})
+}
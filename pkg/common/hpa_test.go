@@ -0,0 +1,108 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestGetMinReplicas(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(int32(2), GetMinReplicas(2, novav1beta1.Autoscaling{}))
+	assert.Equal(int32(2), GetMinReplicas(2, novav1beta1.Autoscaling{Enabled: true}))
+	assert.Equal(int32(3), GetMinReplicas(2, novav1beta1.Autoscaling{Enabled: true, MinReplicas: int32Ptr(3)}))
+	// MinReplicas only apply to an enabled autoscaling
+	assert.Equal(int32(2), GetMinReplicas(2, novav1beta1.Autoscaling{MinReplicas: int32Ptr(3)}))
+}
+
+func TestEnsureHorizontalPodAutoscaler(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaAPI{ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack"}}
+	r := newTestReconciler(instance)
+	cpuMetric := func(utilization int32) autoscalingv2beta2.MetricSpec {
+		return autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		}
+	}
+	memoryMetric := autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: corev1.ResourceMemory,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: int32Ptr(70),
+			},
+		},
+	}
+
+	tests := []struct {
+		autoscaling novav1beta1.Autoscaling
+		minReplicas int32
+		maxReplicas int32
+		metrics     []autoscalingv2beta2.MetricSpec
+	}{
+		// the default CPU target without metrics, MaxReplicas raised to the MinReplicas
+		{novav1beta1.Autoscaling{Enabled: true}, 2, 2, []autoscalingv2beta2.MetricSpec{cpuMetric(80)}},
+		{novav1beta1.Autoscaling{Enabled: true, MinReplicas: int32Ptr(3), MaxReplicas: 6, TargetCPUUtilizationPercentage: int32Ptr(60)},
+			3, 6, []autoscalingv2beta2.MetricSpec{cpuMetric(60)}},
+		// no default CPU target with custom metrics
+		{novav1beta1.Autoscaling{Enabled: true, MaxReplicas: 6, Metrics: []autoscalingv2beta2.MetricSpec{memoryMetric}},
+			2, 6, []autoscalingv2beta2.MetricSpec{memoryMetric}},
+		{novav1beta1.Autoscaling{Enabled: true, MaxReplicas: 6, Metrics: []autoscalingv2beta2.MetricSpec{memoryMetric}, TargetCPUUtilizationPercentage: int32Ptr(60)},
+			2, 6, []autoscalingv2beta2.MetricSpec{memoryMetric, cpuMetric(60)}},
+	}
+	for _, test := range tests {
+		_, err := EnsureHorizontalPodAutoscaler(r, instance, 2, test.autoscaling)
+		assert.NoError(err)
+
+		hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+		err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: "nova-api", Namespace: "openstack"}, hpa)
+		assert.NoError(err)
+		assert.Equal("nova-api", hpa.Spec.ScaleTargetRef.Name)
+		assert.Equal(test.minReplicas, *hpa.Spec.MinReplicas)
+		assert.Equal(test.maxReplicas, hpa.Spec.MaxReplicas)
+		assert.Equal(test.metrics, hpa.Spec.Metrics)
+	}
+
+	// disabled autoscaling removes the HorizontalPodAutoscaler
+	_, err := EnsureHorizontalPodAutoscaler(r, instance, 2, novav1beta1.Autoscaling{})
+	assert.NoError(err)
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: "nova-api", Namespace: "openstack"}, &autoscalingv2beta2.HorizontalPodAutoscaler{})
+	assert.True(k8s_errors.IsNotFound(err))
+}
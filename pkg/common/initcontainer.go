@@ -17,20 +17,27 @@ limitations under the License.
 package common
 
 import (
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // CtrlInitContainer information
 type CtrlInitContainer struct {
-	ContainerImage     string
-	DatabaseHost       string
-	CellDatabase       string
-	APIDatabase        string
-	TransportURLSecret string
-	NovaSecret         string
-	NeutronSecret      string
-	PlacementSecret    string
-	VolumeMounts       []corev1.VolumeMount
+	ContainerImage                  string
+	DatabaseHost                    string
+	CellDatabase                    string
+	APIDatabase                     string
+	DatabaseTLS                     novav1beta1.DatabaseTLS
+	DatabaseUser                    string
+	TransportURLSecret              string
+	NotificationsTransportURLSecret string
+	NovaSecret                      string
+	ApplicationCredentialSecret     string
+	NeutronSecret                   string
+	PlacementSecret                 string
+	PasswordSelectors               novav1beta1.PasswordSelector
+	VolumeMounts                    []corev1.VolumeMount
+	Resources                       corev1.ResourceRequirements
 }
 
 // CmpInitContainer information
@@ -47,6 +54,93 @@ type CmpInitContainer struct {
 // GetCtrlInitContainer - init container for nova ctrl plane services
 func GetCtrlInitContainer(init CtrlInitContainer) []corev1.Container {
 	runAsUser := int64(0)
+	selectors := GetPasswordSelectors(init.PasswordSelectors)
+
+	envs := []corev1.EnvVar{
+		{
+			Name: "TransportURL",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: init.TransportURLSecret,
+					},
+					Key: selectors.TransportURL,
+				},
+			},
+		},
+		{
+			Name:  "DatabaseHost",
+			Value: init.DatabaseHost,
+		},
+		{
+			Name:  "DatabaseConnectionParams",
+			Value: GetDatabaseConnectionParams(init.DatabaseTLS),
+		},
+		{
+			Name:  "CellDatabase",
+			Value: init.CellDatabase,
+		},
+		{
+			Name:  "ApiDatabase",
+			Value: init.APIDatabase,
+		},
+		{
+			Name: "DatabasePassword",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: init.NovaSecret,
+					},
+					Key: selectors.Database,
+				},
+			},
+		},
+		{
+			Name: "NovaKeystoneAuthPassword",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: init.NovaSecret,
+					},
+					Key: selectors.Service,
+				},
+			},
+		},
+		{
+			Name: "NeutronKeystoneAuthPassword",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: init.NeutronSecret,
+					},
+					Key: selectors.Neutron,
+				},
+			},
+		},
+		{
+			Name: "PlacementKeystoneAuthPassword",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: init.PlacementSecret,
+					},
+					Key: selectors.Placement,
+				},
+			},
+		},
+	}
+
+	// the services connect with their own DB account, the jobs with the account of the database
+	if init.DatabaseUser != "" {
+		envs = append(envs, corev1.EnvVar{Name: "DatabaseUser", Value: init.DatabaseUser})
+	}
+	envs = append(envs, GetMetadataProxySharedSecretEnvVar(init.NovaSecret, selectors))
+	if init.NotificationsTransportURLSecret != "" {
+		envs = append(envs, GetNotificationsTransportURLEnvVar(init.NotificationsTransportURLSecret, selectors.TransportURL))
+	}
+	if init.ApplicationCredentialSecret != "" {
+		envs = append(envs, GetApplicationCredentialEnvVars(init.ApplicationCredentialSecret, selectors)...)
+	}
 
 	return []corev1.Container{
 		{
@@ -58,76 +152,55 @@ func GetCtrlInitContainer(init CtrlInitContainer) []corev1.Container {
 			Command: []string{
 				"/bin/bash", "-c", "/usr/local/bin/container-scripts/init.sh",
 			},
-			Env: []corev1.EnvVar{
-				{
-					Name: "TransportURL",
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: init.TransportURLSecret,
-							},
-							Key: "TransportUrl",
-						},
-					},
-				},
-				{
-					Name:  "DatabaseHost",
-					Value: init.DatabaseHost,
-				},
-				{
-					Name:  "CellDatabase",
-					Value: init.CellDatabase,
-				},
-				{
-					Name:  "ApiDatabase",
-					Value: init.APIDatabase,
-				},
-				{
-					Name: "DatabasePassword",
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: init.NovaSecret,
-							},
-							Key: "DatabasePassword",
-						},
-					},
-				},
-				{
-					Name: "NovaKeystoneAuthPassword",
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: init.NovaSecret,
-							},
-							Key: "NovaKeystoneAuthPassword",
-						},
+			Env:          envs,
+			VolumeMounts: init.VolumeMounts,
+			Resources:    init.Resources,
+		},
+	}
+}
+
+// GetApplicationCredentialEnvVars - init container env vars with the keystone application credential of the nova service
+func GetApplicationCredentialEnvVars(secret string, selectors novav1beta1.PasswordSelector) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: "ApplicationCredentialID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secret,
 					},
+					Key: selectors.ApplicationCredentialID,
 				},
-				{
-					Name: "NeutronKeystoneAuthPassword",
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: init.NeutronSecret,
-							},
-							Key: "NeutronKeystoneAuthPassword",
-						},
+			},
+		},
+		{
+			Name: "ApplicationCredentialSecret",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secret,
 					},
+					Key: selectors.ApplicationCredentialSecret,
 				},
-				{
-					Name: "PlacementKeystoneAuthPassword",
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: init.PlacementSecret,
-							},
-							Key: "PlacementKeystoneAuthPassword",
-						},
-					},
+			},
+		},
+	}
+}
+
+// GetMetadataProxySharedSecretEnvVar - init container env var with the metadata proxy shared secret, optional
+// as only a generated NovaSecret is guaranteed to contain it
+func GetMetadataProxySharedSecretEnvVar(secret string, selectors novav1beta1.PasswordSelector) corev1.EnvVar {
+	optional := true
+	return corev1.EnvVar{
+		Name: "MetadataProxySharedSecret",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
 				},
+				Key:      selectors.Metadata,
+				Optional: &optional,
 			},
-			VolumeMounts: init.VolumeMounts,
 		},
 	}
 }
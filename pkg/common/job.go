@@ -0,0 +1,216 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// JobHashAnnotation - annotation holding the hash of the job definition
+	JobHashAnnotation = "nova.openstack.org/job-hash"
+	// DefaultJobRetryLimit - retries of a job pod before the job is considered failed
+	DefaultJobRetryLimit int32 = 6
+	// FailedJobRetryInterval - time after which a failed job gets replaced to retry it
+	FailedJobRetryInterval = 5 * time.Minute
+	// jobLogTailLines - number of log lines of a failed job pod to report
+	jobLogTailLines int64 = 20
+)
+
+// JobFailedError - details of a job which exhausted its retries
+type JobFailedError struct {
+	JobName  string
+	PodName  string
+	ExitCode int32
+	LogTail  string
+	// time until the failed job gets replaced to retry it
+	RetryAfter time.Duration
+}
+
+func (e *JobFailedError) Error() string {
+	msg := fmt.Sprintf("Job %s failed", e.JobName)
+	if e.PodName != "" {
+		msg = fmt.Sprintf("%s, pod %s exited with code %d", msg, e.PodName, e.ExitCode)
+	}
+	if e.RetryAfter > 0 {
+		msg = fmt.Sprintf("%s, retrying in %s or delete the job to retry now", msg, e.RetryAfter.Round(time.Second))
+	}
+	if e.LogTail != "" {
+		msg = fmt.Sprintf("%s:\n%s", msg, e.LogTail)
+	}
+	return msg
+}
+
+// IsJobFailed - returns true if the error reports a failed job
+func IsJobFailed(err error) bool {
+	_, ok := err.(*JobFailedError)
+	return ok
+}
+
+// GetJobRetryAfter - returns the time until a failed job gets retried, 0 if the error does not report a failed job
+func GetJobRetryAfter(err error) time.Duration {
+	if jobErr, ok := err.(*JobFailedError); ok {
+		return jobErr.RetryAfter
+	}
+	return 0
+}
+
+// GetFailedJobRetryAfter - returns the time until a job which failed at failedAt gets retried
+func GetFailedJobRetryAfter(failedAt time.Time, now time.Time) time.Duration {
+	retryAfter := failedAt.Add(FailedJobRetryInterval).Sub(now)
+	if retryAfter < 0 {
+		return 0
+	}
+	return retryAfter
+}
+
+// GetJobRetryLimit - returns the job retry limit, or the default if not set
+func GetJobRetryLimit(retryLimit int32) *int32 {
+	if retryLimit <= 0 {
+		retryLimit = DefaultJobRetryLimit
+	}
+	return &retryLimit
+}
+
+// EnsureJob - create the job if it does not exist and return true while it is running.
+// Failed pods are retried by kubernetes with an exponential back-off up to the
+// job BackoffLimit. A job which exhausted its retries is kept for debugging for the
+// FailedJobRetryInterval and a JobFailedError with the exit code and log tail of the
+// last failed pod is returned, afterwards it gets replaced to retry. A job created from a different definition (hash) gets replaced.
+func EnsureJob(r ReconcilerCommon, kclient kubernetes.Interface, job *batchv1.Job, hash string) (bool, error) {
+	foundJob := &batchv1.Job{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, foundJob)
+	if err != nil && k8s_errors.IsNotFound(err) {
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[JobHashAnnotation] = hash
+
+		r.GetLogger().Info("Creating a new Job", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		err = r.GetClient().Create(context.TODO(), job)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	} else if err != nil {
+		return true, err
+	}
+
+	// job definition changed, e.g. after fixing the cause of a failed job
+	if foundJob.Annotations[JobHashAnnotation] != hash {
+		r.GetLogger().Info("Job definition changed, replacing it", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		_, err = util.DeleteJob(foundJob, kclient, r.GetLogger())
+		return true, err
+	}
+
+	for _, c := range foundJob.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			retryAfter := GetFailedJobRetryAfter(c.LastTransitionTime.Time, time.Now())
+			if retryAfter == 0 {
+				r.GetLogger().Info("Job Status Failed, replacing it to retry", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+				_, err = util.DeleteJob(foundJob, kclient, r.GetLogger())
+				return true, err
+			}
+			r.GetLogger().Info("Job Status Failed", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+			return false, getJobFailure(r, kclient, foundJob, retryAfter)
+		}
+	}
+
+	if foundJob.Status.Succeeded > 0 {
+		r.GetLogger().Info("Job Status Successful", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		return false, nil
+	}
+
+	r.GetLogger().Info("Job Status incomplete... requeuing", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+	return true, nil
+}
+
+// getJobFailure - collect exit code and log tail of the last failed pod of the job
+func getJobFailure(r ReconcilerCommon, kclient kubernetes.Interface, job *batchv1.Job, retryAfter time.Duration) error {
+	jobErr := &JobFailedError{JobName: job.Name, RetryAfter: retryAfter}
+
+	pods, err := GetAllPodsWithLabel(kclient, r.GetLogger(), map[string]string{"job-name": job.Name}, job.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var failedPod *corev1.Pod
+	for i, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if failedPod == nil || failedPod.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			failedPod = &pods.Items[i]
+		}
+	}
+	if failedPod == nil {
+		return jobErr
+	}
+	jobErr.PodName = failedPod.Name
+
+	// init container failures prevent the job container from running
+	statuses := append(failedPod.Status.InitContainerStatuses, failedPod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Terminated == nil || status.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		jobErr.ExitCode = status.State.Terminated.ExitCode
+
+		tailLines := jobLogTailLines
+		logs, err := kclient.CoreV1().Pods(failedPod.Namespace).GetLogs(failedPod.Name, &corev1.PodLogOptions{
+			Container: status.Name,
+			TailLines: &tailLines,
+		}).DoRaw(context.TODO())
+		if err == nil {
+			jobErr.LogTail = strings.TrimSpace(string(logs))
+		}
+		break
+	}
+
+	return jobErr
+}
+
+// GetJobCondition - returns the status condition for a job
+func GetJobCondition(conditionType novav1beta1.ConditionType, requeue bool, err error) novav1beta1.Condition {
+	condition := novav1beta1.Condition{
+		Type:    conditionType,
+		Status:  corev1.ConditionTrue,
+		Reason:  novav1beta1.JobCompletedReason,
+		Message: "Job completed",
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = novav1beta1.JobFailedReason
+		condition.Message = err.Error()
+	} else if requeue {
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = novav1beta1.JobRunningReason
+		condition.Message = "Job running"
+	}
+	return condition
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobFailedError(t *testing.T) {
+	assert := assert.New(t)
+
+	err := &JobFailedError{JobName: "nova-db-sync"}
+	assert.Equal("Job nova-db-sync failed", err.Error())
+	assert.True(IsJobFailed(err))
+	assert.False(IsJobFailed(fmt.Errorf("Job nova-db-sync failed")))
+
+	err = &JobFailedError{JobName: "nova-db-sync", PodName: "nova-db-sync-abcde", ExitCode: 1, LogTail: "error", RetryAfter: 90 * time.Second}
+	assert.Equal("Job nova-db-sync failed, pod nova-db-sync-abcde exited with code 1, retrying in 1m30s or delete the job to retry now:\nerror", err.Error())
+	assert.Equal(90*time.Second, GetJobRetryAfter(err))
+	assert.Equal(time.Duration(0), GetJobRetryAfter(fmt.Errorf("error")))
+}
+
+func TestJobRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultJobRetryLimit, *GetJobRetryLimit(0))
+	assert.Equal(int32(3), *GetJobRetryLimit(3))
+
+	now := time.Now()
+	assert.Equal(FailedJobRetryInterval, GetFailedJobRetryAfter(now, now))
+	assert.Equal(time.Minute, GetFailedJobRetryAfter(now.Add(time.Minute-FailedJobRetryInterval), now))
+	assert.Equal(time.Duration(0), GetFailedJobRetryAfter(now.Add(-FailedJobRetryInterval), now))
+	assert.Equal(time.Duration(0), GetFailedJobRetryAfter(now.Add(-time.Hour), now))
+}
+
+func TestJobCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		requeue bool
+		err     error
+		status  corev1.ConditionStatus
+		reason  string
+	}{
+		{false, nil, corev1.ConditionTrue, novav1beta1.JobCompletedReason},
+		{true, nil, corev1.ConditionUnknown, novav1beta1.JobRunningReason},
+		{false, &JobFailedError{JobName: "nova-db-sync"}, corev1.ConditionFalse, novav1beta1.JobFailedReason},
+	}
+	for _, test := range tests {
+		condition := GetJobCondition(novav1beta1.DbSyncCondition, test.requeue, test.err)
+		assert.Equal(novav1beta1.DbSyncCondition, condition.Type)
+		assert.Equal(test.status, condition.Status)
+		assert.Equal(test.reason, condition.Reason)
+	}
+	assert.Equal("Job nova-db-sync failed", GetJobCondition(novav1beta1.DbSyncCondition, false, &JobFailedError{JobName: "nova-db-sync"}).Message)
+}
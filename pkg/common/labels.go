@@ -16,6 +16,11 @@ limitations under the License.
 
 package common
 
+const (
+	// CellMappingsHashAnnotation - pod template annotation holding the hash of the created cells
+	CellMappingsHashAnnotation = "nova.openstack.org/cell-mappings-hash"
+)
+
 // GetLabels - get labels to be set on objects created by controller
 func GetLabels(name string, appLabel string) map[string]string {
 	return map[string]string{
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// MemcachedIndexField - field index of the Memcached instance referenced in the Spec of a CR
+const MemcachedIndexField = ".spec.memcachedInstance"
+
+// memcachedGroupVersionKind - kind of the Memcached instances of the memcached operator
+var memcachedGroupVersionKind = schema.GroupVersionKind{Group: "memcached.openstack.org", Version: "v1beta1", Kind: "Memcached"}
+
+// GetMemcachedServers - comma separated host:port list of the servers of the Memcached instance.
+// Returns an empty string while the instance did not report its servers.
+func GetMemcachedServers(r ReconcilerCommon, name string, namespace string) (string, error) {
+	memcached := &unstructured.Unstructured{}
+	memcached.SetGroupVersionKind(memcachedGroupVersionKind)
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, memcached)
+	if err != nil {
+		return "", err
+	}
+
+	servers, _, err := unstructured.NestedStringSlice(memcached.UnstructuredContent(), "status", "serverList")
+	if err != nil || len(servers) == 0 {
+		r.GetLogger().Info(fmt.Sprintf("Waiting on Memcached %s servers...", name))
+		return "", err
+	}
+
+	return strings.Join(servers, ","), nil
+}
+
+// MemcachedIndexFunc - field indexer returning the name of the Memcached instance referenced in the Spec of a CR
+func MemcachedIndexFunc(obj runtime.Object) []string {
+	cr := struct {
+		Spec struct {
+			MemcachedInstance string `json:"memcachedInstance"`
+		} `json:"spec"`
+	}{}
+	inrec, _ := json.Marshal(obj)
+	json.Unmarshal(inrec, &cr)
+
+	if cr.Spec.MemcachedInstance == "" {
+		return []string{}
+	}
+	return []string{cr.Spec.MemcachedInstance}
+}
+
+// WatchMemcached - reconcile the CRs on changes of the referenced Memcached instance to render the
+// new server list. Skipped if the Memcached CRD is not installed.
+func WatchMemcached(mgr ctrl.Manager, c controller.Controller, r ReconcilerCommon, obj runtime.Object, list runtime.Object) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(memcachedGroupVersionKind.GroupKind(), memcachedGroupVersionKind.Version)
+	if meta.IsNoMatchError(err) {
+		r.GetLogger().Info("Memcached CRD not installed, not watching the Memcached instances")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), obj, MemcachedIndexField, MemcachedIndexFunc)
+	if err != nil {
+		return err
+	}
+
+	memcached := &unstructured.Unstructured{}
+	memcached.SetGroupVersionKind(memcachedGroupVersionKind)
+	return c.Watch(&source.Kind{Type: memcached}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: getIndexWatchFn(r, list, MemcachedIndexField, "Memcached"),
+	})
+}
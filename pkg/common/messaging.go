@@ -0,0 +1,242 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// RabbitMQPort - AMQP port of the RabbitMQ cluster service
+	RabbitMQPort = 5672
+	// MessagingBusIndexField - field index of the RabbitMQ clusters referenced in the Spec of a CR
+	MessagingBusIndexField = ".spec.messagingBus.cluster"
+)
+
+// rabbitmqGroupVersion - API of the RabbitMQ cluster and topology operators
+var rabbitmqGroupVersion = schema.GroupVersion{Group: "rabbitmq.com", Version: "v1beta1"}
+
+// TransportURL - vhost and user to create on a RabbitMQ cluster for a transport url
+type TransportURL struct {
+	// name of the vhost, user and permission
+	Name string
+	// name of the RabbitmqCluster
+	Cluster string
+	// key of the transport url in the secret
+	SecretKey string
+}
+
+// options to set in the RabbitMQ templates
+type transportURLOptions struct {
+	Name    string
+	Cluster string
+}
+
+// GetTransportURLSecretName - name of the generated transport url secret
+func GetTransportURLSecretName(name string) string {
+	return fmt.Sprintf("%s-transport-url", name)
+}
+
+// GetTransportURL - transport url of the vhost on the RabbitMQ cluster service
+func GetTransportURL(username string, password string, serviceName string, namespace string, vhost string) string {
+	transportURL := url.URL{
+		Scheme: "rabbit",
+		User:   url.UserPassword(username, password),
+		Host:   fmt.Sprintf("%s.%s.svc:%d", serviceName, namespace, RabbitMQPort),
+		Path:   "/" + vhost,
+	}
+	return transportURL.String()
+}
+
+// rabbitmqObject - render the RabbitMQ object from the template of the kind
+func rabbitmqObject(obj metav1.Object, kind string, t TransportURL) (unstructured.Unstructured, error) {
+	opts := transportURLOptions{
+		t.Name,
+		t.Cluster,
+	}
+
+	templatesPath := util.GetTemplatesPath()
+
+	rabbitmqTemplate := fmt.Sprintf("%s/common/internal/rabbitmq_%s.yaml", templatesPath, kind)
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(util.ExecuteTemplate(rabbitmqTemplate, &opts)), 4096)
+	u := unstructured.Unstructured{}
+	err := decoder.Decode(&u)
+	u.SetNamespace(obj.GetNamespace())
+
+	return u, err
+}
+
+// EnsureTransportURL - create a vhost and a user with permissions on it on the RabbitMQ cluster and
+// write the transport url secret. Returns the secret name, or an empty string while the cluster
+// and the user credentials are not ready.
+func EnsureTransportURL(r ReconcilerCommon, obj metav1.Object, t TransportURL) (string, error) {
+	for _, kind := range []string{"vhost", "user", "permission"} {
+		rabbitmqObj, err := rabbitmqObject(obj, kind, t)
+		if err != nil {
+			return "", err
+		}
+
+		err = controllerutil.SetControllerReference(obj, &rabbitmqObj, r.GetScheme())
+		if err != nil {
+			return "", err
+		}
+
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(rabbitmqObj.GroupVersionKind())
+		err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: rabbitmqObj.GetName(), Namespace: rabbitmqObj.GetNamespace()}, found)
+		if err != nil && k8s_errors.IsNotFound(err) {
+			r.GetLogger().Info(fmt.Sprintf("Creating RabbitMQ %s %s", rabbitmqObj.GetKind(), rabbitmqObj.GetName()))
+			err = r.GetClient().Create(context.TODO(), &rabbitmqObj)
+			if err != nil {
+				return "", err
+			}
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	// RabbitMQ cluster service
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(rabbitmqGroupVersion.WithKind("RabbitmqCluster"))
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: t.Cluster, Namespace: obj.GetNamespace()}, cluster)
+	if err != nil {
+		return "", err
+	}
+	serviceName, _, err := unstructured.NestedString(cluster.UnstructuredContent(), "status", "defaultUser", "serviceReference", "name")
+	if err != nil || serviceName == "" {
+		r.GetLogger().Info(fmt.Sprintf("Waiting on RabbitMQ cluster %s service...", t.Cluster))
+		return "", err
+	}
+
+	// credentials of the user get generated by the RabbitMQ topology operator
+	user := &unstructured.Unstructured{}
+	user.SetGroupVersionKind(rabbitmqGroupVersion.WithKind("User"))
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: t.Name, Namespace: obj.GetNamespace()}, user)
+	if err != nil {
+		return "", err
+	}
+	credentialsSecret, _, err := unstructured.NestedString(user.UnstructuredContent(), "status", "credentials", "name")
+	if err != nil || credentialsSecret == "" {
+		r.GetLogger().Info(fmt.Sprintf("Waiting on RabbitMQ user %s credentials...", t.Name))
+		return "", err
+	}
+	credentials, _, err := GetSecret(r.GetClient(), credentialsSecret, obj.GetNamespace(), "username", "password")
+	if err != nil {
+		return "", err
+	}
+
+	transportURL := GetTransportURL(string(credentials.Data["username"]), string(credentials.Data["password"]), serviceName, obj.GetNamespace(), t.Name)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetTransportURLSecretName(t.Name),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), r.GetClient(), secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			t.SecretKey: []byte(transportURL),
+		}
+
+		return controllerutil.SetControllerReference(obj, secret, r.GetScheme())
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Name, nil
+}
+
+// MessagingBusIndexFunc - field indexer returning the names of all RabbitMQ clusters referenced in the Spec of a CR
+func MessagingBusIndexFunc(obj runtime.Object) []string {
+	cr := struct {
+		Spec struct {
+			MessagingBus novav1beta1.MessagingBus `json:"messagingBus"`
+			Cells        []struct {
+				MessagingBus novav1beta1.MessagingBus `json:"messagingBus"`
+			} `json:"cells"`
+		} `json:"spec"`
+	}{}
+	inrec, _ := json.Marshal(obj)
+	json.Unmarshal(inrec, &cr)
+
+	clusters := []string{}
+	if cr.Spec.MessagingBus.Cluster != "" {
+		clusters = append(clusters, cr.Spec.MessagingBus.Cluster)
+	}
+	for _, cell := range cr.Spec.Cells {
+		if cell.MessagingBus.Cluster != "" {
+			clusters = append(clusters, cell.MessagingBus.Cluster)
+		}
+	}
+
+	return clusters
+}
+
+// WatchMessagingBus - reconcile the CRs on changes of their RabbitMQ vhosts, users and permissions and
+// of the referenced RabbitMQ clusters. Skipped if the RabbitMQ CRDs are not installed.
+func WatchMessagingBus(mgr ctrl.Manager, c controller.Controller, r ReconcilerCommon, owner runtime.Object, list runtime.Object) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(rabbitmqGroupVersion.WithKind("RabbitmqCluster").GroupKind(), rabbitmqGroupVersion.Version)
+	if meta.IsNoMatchError(err) {
+		r.GetLogger().Info("RabbitMQ CRDs not installed, not watching the messaging bus")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), owner, MessagingBusIndexField, MessagingBusIndexFunc)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range []string{"Vhost", "User", "Permission"} {
+		rabbitmqObj := &unstructured.Unstructured{}
+		rabbitmqObj.SetGroupVersionKind(rabbitmqGroupVersion.WithKind(kind))
+		err = c.Watch(&source.Kind{Type: rabbitmqObj}, &handler.EnqueueRequestForOwner{OwnerType: owner, IsController: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(rabbitmqGroupVersion.WithKind("RabbitmqCluster"))
+	return c.Watch(&source.Kind{Type: cluster}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: getIndexWatchFn(r, list, MessagingBusIndexField, "RabbitmqCluster"),
+	})
+}
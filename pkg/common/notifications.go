@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// NotificationsDriver - oslo.messaging driver of enabled notifications
+	NotificationsDriver = "messagingv2"
+	// NotificationsNoopDriver - oslo.messaging driver of disabled notifications
+	NotificationsNoopDriver = "noop"
+	// NotificationsDefaultFormat - default nova notification format
+	NotificationsDefaultFormat = "unversioned"
+	// NotificationsDefaultTopic - default topic to emit the notifications on
+	NotificationsDefaultTopic = "notifications"
+)
+
+// GetNotificationsConfigOptions - template parameters of the [oslo_messaging_notifications]
+// and [notifications] sections of nova.conf
+func GetNotificationsConfigOptions(notifications novav1beta1.Notifications) map[string]string {
+	driver := NotificationsNoopDriver
+	if notifications.Enabled {
+		driver = NotificationsDriver
+	}
+
+	format := notifications.Format
+	if format == "" {
+		format = NotificationsDefaultFormat
+	}
+
+	topics := notifications.Topics
+	if len(topics) == 0 {
+		topics = []string{NotificationsDefaultTopic}
+	}
+
+	return map[string]string{
+		"NotificationsDriver": driver,
+		"NotificationsFormat": format,
+		"NotificationsTopics": strings.Join(topics, ","),
+	}
+}
+
+// GetNotificationsTransportURLEnvVar - init container env var with the transport url of the notifications bus
+func GetNotificationsTransportURLEnvVar(secret string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "NotificationsTransportURL",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+				Key: key,
+			},
+		},
+	}
+}
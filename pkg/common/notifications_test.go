@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotificationsConfigOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		notifications novav1beta1.Notifications
+		expected      map[string]string
+	}{
+		// disabled notifications use the noop driver
+		{
+			novav1beta1.Notifications{},
+			map[string]string{
+				"NotificationsDriver": "noop",
+				"NotificationsFormat": "unversioned",
+				"NotificationsTopics": "notifications",
+			},
+		},
+		{
+			novav1beta1.Notifications{Enabled: true},
+			map[string]string{
+				"NotificationsDriver": "messagingv2",
+				"NotificationsFormat": "unversioned",
+				"NotificationsTopics": "notifications",
+			},
+		},
+		{
+			novav1beta1.Notifications{Enabled: true, Format: "versioned", Topics: []string{"notifications", "ceilometer"}},
+			map[string]string{
+				"NotificationsDriver": "messagingv2",
+				"NotificationsFormat": "versioned",
+				"NotificationsTopics": "notifications,ceilometer",
+			},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(test.expected, GetNotificationsConfigOptions(test.notifications))
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnsurePodDisruptionBudget - creates or updates the PodDisruptionBudget of the pods of a service
+// with more than one replica and deletes it when the service is scaled down to a single replica
+func EnsurePodDisruptionBudget(r ReconcilerCommon, obj metav1.Object, replicas int32, labels map[string]string, budget novav1beta1.PodDisruptionBudget) (controllerutil.OperationResult, error) {
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+
+	// a budget on a single replica would block the drain of its node
+	if replicas <= 1 {
+		err := r.GetClient().Delete(context.TODO(), pdb)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return controllerutil.OperationResultNone, err
+		}
+		return controllerutil.OperationResultNone, nil
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), r.GetClient(), pdb, func() error {
+		pdb.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: labels,
+		}
+		pdb.Spec.MinAvailable = nil
+		pdb.Spec.MaxUnavailable = nil
+
+		switch {
+		case budget.MinAvailable != nil:
+			pdb.Spec.MinAvailable = budget.MinAvailable
+		case budget.MaxUnavailable != nil:
+			pdb.Spec.MaxUnavailable = budget.MaxUnavailable
+		default:
+			maxUnavailable := intstr.FromInt(1)
+			pdb.Spec.MaxUnavailable = &maxUnavailable
+		}
+
+		return controllerutil.SetControllerReference(obj, pdb, r.GetScheme())
+	})
+}
+
+// GetRollingUpdateStrategy - rolling update strategy of the deployment of a service, a rollout takes down
+// at most the maxUnavailable of the disruption budget, one pod at a time by default or with a minAvailable
+func GetRollingUpdateStrategy(budget novav1beta1.PodDisruptionBudget) appsv1.DeploymentStrategy {
+	maxUnavailable := intstr.FromInt(1)
+	if budget.MinAvailable == nil && budget.MaxUnavailable != nil {
+		maxUnavailable = *budget.MaxUnavailable
+	}
+
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestEnsurePodDisruptionBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaAPI{ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack"}}
+	r := newTestReconciler(instance)
+	labels := map[string]string{"app": "nova-api"}
+	minAvailable := intstr.FromInt(2)
+	maxUnavailable := intstr.FromString("50%")
+	defaultMaxUnavailable := intstr.FromInt(1)
+
+	tests := []struct {
+		budget         novav1beta1.PodDisruptionBudget
+		minAvailable   *intstr.IntOrString
+		maxUnavailable *intstr.IntOrString
+	}{
+		// one pod at a time by default
+		{novav1beta1.PodDisruptionBudget{}, nil, &defaultMaxUnavailable},
+		{novav1beta1.PodDisruptionBudget{MaxUnavailable: &maxUnavailable}, nil, &maxUnavailable},
+		{novav1beta1.PodDisruptionBudget{MinAvailable: &minAvailable}, &minAvailable, nil},
+		// MinAvailable takes precedence
+		{novav1beta1.PodDisruptionBudget{MinAvailable: &minAvailable, MaxUnavailable: &maxUnavailable}, &minAvailable, nil},
+	}
+	for _, test := range tests {
+		_, err := EnsurePodDisruptionBudget(r, instance, 3, labels, test.budget)
+		assert.NoError(err)
+
+		pdb := &policyv1beta1.PodDisruptionBudget{}
+		err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: "nova-api", Namespace: "openstack"}, pdb)
+		assert.NoError(err)
+		assert.Equal(labels, pdb.Spec.Selector.MatchLabels)
+		assert.Equal(test.minAvailable, pdb.Spec.MinAvailable, test.budget)
+		assert.Equal(test.maxUnavailable, pdb.Spec.MaxUnavailable, test.budget)
+	}
+
+	// no budget on a single replica
+	_, err := EnsurePodDisruptionBudget(r, instance, 1, labels, novav1beta1.PodDisruptionBudget{})
+	assert.NoError(err)
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: "nova-api", Namespace: "openstack"}, &policyv1beta1.PodDisruptionBudget{})
+	assert.True(k8s_errors.IsNotFound(err))
+}
+
+func TestGetRollingUpdateStrategy(t *testing.T) {
+	assert := assert.New(t)
+
+	minAvailable := intstr.FromInt(2)
+	maxUnavailable := intstr.FromString("50%")
+	defaultMaxUnavailable := intstr.FromInt(1)
+
+	tests := []struct {
+		budget         novav1beta1.PodDisruptionBudget
+		maxUnavailable intstr.IntOrString
+	}{
+		// one pod at a time by default
+		{novav1beta1.PodDisruptionBudget{}, defaultMaxUnavailable},
+		{novav1beta1.PodDisruptionBudget{MaxUnavailable: &maxUnavailable}, maxUnavailable},
+		// MinAvailable can not be translated, one pod at a time
+		{novav1beta1.PodDisruptionBudget{MinAvailable: &minAvailable}, defaultMaxUnavailable},
+		{novav1beta1.PodDisruptionBudget{MinAvailable: &minAvailable, MaxUnavailable: &maxUnavailable}, defaultMaxUnavailable},
+	}
+	for _, test := range tests {
+		strategy := GetRollingUpdateStrategy(test.budget)
+		assert.Equal(appsv1.RollingUpdateDeploymentStrategyType, strategy.Type)
+		assert.Equal(test.maxUnavailable, *strategy.RollingUpdate.MaxUnavailable, test.budget)
+	}
+}
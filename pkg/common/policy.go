@@ -0,0 +1,169 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// PolicyFile - key of the policy overrides in the policy ConfigMaps
+	PolicyFile = "policy.yaml"
+	// PolicyPath - path of the policy overrides in the api and metadata containers
+	PolicyPath = "/etc/nova/" + PolicyFile
+)
+
+// InvalidPolicyError - the policy overrides are missing or no valid YAML
+type InvalidPolicyError struct {
+	Reason string
+}
+
+func (e *InvalidPolicyError) Error() string {
+	return fmt.Sprintf("Invalid policy: %s", e.Reason)
+}
+
+// IsInvalidPolicy - returns true if the error reports invalid policy overrides
+func IsInvalidPolicy(err error) bool {
+	_, ok := err.(*InvalidPolicyError)
+	return ok
+}
+
+// GetPolicyConfigMapName - name of the ConfigMap with the validated policy overrides of a CR
+func GetPolicyConfigMapName(name string) string {
+	return fmt.Sprintf("%s-policy", name)
+}
+
+// getPolicy - the inline policy overrides or the ones of the referenced ConfigMap
+func getPolicy(r ReconcilerCommon, namespace string, policy novav1beta1.Policy) (string, error) {
+	if policy.ConfigMap == "" {
+		return policy.Inline, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: policy.ConfigMap, Namespace: namespace}, configMap)
+	if err != nil && k8s_errors.IsNotFound(err) {
+		// the ConfigMap watch triggers the reconcile once it got created
+		return "", &InvalidPolicyError{Reason: fmt.Sprintf("ConfigMap %s not found", policy.ConfigMap)}
+	} else if err != nil {
+		return "", err
+	}
+	data, ok := configMap.Data[PolicyFile]
+	if !ok {
+		return "", &InvalidPolicyError{Reason: fmt.Sprintf("ConfigMap %s is missing the %s key", policy.ConfigMap, PolicyFile)}
+	}
+
+	return data, nil
+}
+
+// EnsurePolicy - validate the policy overrides as YAML mapping of rule names to rules and write them
+// to the policy ConfigMap of the CR. Returns the hash of the policy, or an empty string if no policy is set,
+// in which case a policy ConfigMap left from previously set overrides gets deleted.
+func EnsurePolicy(r ReconcilerCommon, obj metav1.Object, policy novav1beta1.Policy) (string, error) {
+	if policy.Inline == "" && policy.ConfigMap == "" {
+		configMap := &corev1.ConfigMap{}
+		err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: GetPolicyConfigMapName(obj.GetName()), Namespace: obj.GetNamespace()}, configMap)
+		if err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(configMap, obj) {
+			return "", nil
+		}
+		r.GetLogger().Info(fmt.Sprintf("Policy overrides unset, deleting ConfigMap %s", configMap.Name))
+		err = r.GetClient().Delete(context.TODO(), configMap)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return "", err
+		}
+		return "", nil
+	}
+
+	data, err := getPolicy(r, obj.GetNamespace(), policy)
+	if err != nil {
+		return "", err
+	}
+
+	rules := map[string]string{}
+	// an empty policy file keeps the default rules
+	err = yaml.NewYAMLOrJSONDecoder(strings.NewReader(data), 4096).Decode(&rules)
+	if err != nil && err != io.EOF {
+		return "", &InvalidPolicyError{Reason: err.Error()}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetPolicyConfigMapName(obj.GetName()),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), r.GetClient(), configMap, func() error {
+		configMap.Data = map[string]string{
+			PolicyFile: data,
+		}
+
+		return controllerutil.SetControllerReference(obj, configMap, r.GetScheme())
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return util.ObjectHash(configMap.Data)
+}
+
+// GetPolicyCondition - Policy condition of the validated policy overrides
+func GetPolicyCondition(err error) novav1beta1.Condition {
+	condition := novav1beta1.Condition{
+		Type:    novav1beta1.PolicyCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  novav1beta1.PolicyValidReason,
+		Message: "Policy overrides are valid",
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = novav1beta1.PolicyInvalidReason
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
+// HandlePolicyError - invalid policy overrides get reported in the Policy condition and the reconcile
+// stops without rolling the pods. Other errors requeue.
+func HandlePolicyError(r ReconcilerCommon, obj runtime.Object, conditions *[]novav1beta1.Condition, err error) (ctrl.Result, error) {
+	if !IsInvalidPolicy(err) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+
+	r.GetLogger().Info(err.Error())
+	if condErr := UpdateStatusCondition(r, obj, conditions, GetPolicyCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	return ctrl.Result{}, nil
+}
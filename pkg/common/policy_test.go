@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestEnsurePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaAPI{ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack", UID: "1234"}}
+	r := newTestReconciler(instance, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-policy", Namespace: "openstack"},
+		Data:       map[string]string{PolicyFile: `"os_compute_api:servers:create": "role:member"`},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-policy-invalid", Namespace: "openstack"},
+		Data:       map[string]string{"policy.json": "{}"},
+	})
+
+	tests := []struct {
+		policy  novav1beta1.Policy
+		invalid bool
+	}{
+		{novav1beta1.Policy{Inline: `"os_compute_api:servers:create": "role:member"`}, false},
+		{novav1beta1.Policy{Inline: `{"os_compute_api:servers:create": "role:member"}`}, false},
+		// comments only keep the default rules
+		{novav1beta1.Policy{Inline: "# no overrides"}, false},
+		{novav1beta1.Policy{ConfigMap: "custom-policy"}, false},
+		// rules have to be a mapping of rule names to rule strings
+		{novav1beta1.Policy{Inline: "- os_compute_api:servers:create"}, true},
+		{novav1beta1.Policy{Inline: `"os_compute_api:servers:create": ["role:member"]`}, true},
+		{novav1beta1.Policy{Inline: `"os_compute_api:servers:create": "role:member`}, true},
+		{novav1beta1.Policy{ConfigMap: "custom-policy-invalid"}, true},
+		{novav1beta1.Policy{ConfigMap: "missing-policy"}, true},
+	}
+	for _, test := range tests {
+		hash, err := EnsurePolicy(r, instance, test.policy)
+		if test.invalid {
+			assert.True(IsInvalidPolicy(err), test.policy)
+			assert.Empty(hash)
+		} else {
+			assert.NoError(err, test.policy)
+			assert.NotEmpty(hash)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: "nova-api-policy", Namespace: "openstack"}, configMap)
+	assert.NoError(err)
+	assert.Equal(`"os_compute_api:servers:create": "role:member"`, configMap.Data[PolicyFile])
+
+	// the policy ConfigMap gets deleted once the overrides are unset
+	hash, err := EnsurePolicy(r, instance, novav1beta1.Policy{})
+	assert.NoError(err)
+	assert.Empty(hash)
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: "nova-api-policy", Namespace: "openstack"}, configMap)
+	assert.True(k8s_errors.IsNotFound(err))
+}
+
+func TestHandlePolicyError(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaAPI{ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack"}}
+	r := newTestReconciler(instance)
+
+	// a missing policy ConfigMap gets reported in the Policy condition without a requeue
+	_, err := EnsurePolicy(r, instance, novav1beta1.Policy{ConfigMap: "missing-policy"})
+	result, err := HandlePolicyError(r, instance, &instance.Status.Conditions, err)
+	assert.NoError(err)
+	assert.Equal(ctrl.Result{}, result)
+	assert.Len(instance.Status.Conditions, 1)
+	assert.Equal(novav1beta1.PolicyCondition, instance.Status.Conditions[0].Type)
+	assert.Equal(corev1.ConditionFalse, instance.Status.Conditions[0].Status)
+	assert.Equal(novav1beta1.PolicyInvalidReason, instance.Status.Conditions[0].Reason)
+	assert.Equal("Invalid policy: ConfigMap missing-policy not found", instance.Status.Conditions[0].Message)
+}
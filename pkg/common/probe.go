@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetProbe - container probe running the handler, unset fields of the probe use the defaults
+func GetProbe(handler corev1.Handler, probe novav1beta1.Probe, defaults novav1beta1.Probe) *corev1.Probe {
+	if probe.InitialDelaySeconds == 0 {
+		probe.InitialDelaySeconds = defaults.InitialDelaySeconds
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = defaults.PeriodSeconds
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = defaults.FailureThreshold
+	}
+
+	return &corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+}
+
+// HealthCheckScript - heartbeat based health check of the RPC services in the scripts configmap
+const HealthCheckScript = "/usr/local/bin/container-scripts/healthcheck.sh"
+
+const (
+	// HealthCheckLiveness - checks the service process is connected to the message bus
+	HealthCheckLiveness = "liveness"
+	// HealthCheckReadiness - checks in addition the heartbeat of the service record in the DB
+	HealthCheckReadiness = "readiness"
+)
+
+var (
+	// the liveness check only inspects /proc of the container
+	healthCheckLivenessDefaults = novav1beta1.Probe{PeriodSeconds: 30, TimeoutSeconds: 10, FailureThreshold: 3}
+	// the readiness check imports nova, which is slow compared to an HTTP probe
+	healthCheckReadinessDefaults = novav1beta1.Probe{PeriodSeconds: 30, TimeoutSeconds: 30, FailureThreshold: 3}
+	// the service record gets created after the first connection to the message bus
+	healthCheckStartupDefaults = novav1beta1.Probe{PeriodSeconds: 10, TimeoutSeconds: 30, FailureThreshold: 30}
+)
+
+// getHealthCheckHandler - exec of the health check script for the service binary
+func getHealthCheckHandler(binary string, check string) corev1.Handler {
+	return corev1.Handler{
+		Exec: &corev1.ExecAction{
+			Command: []string{
+				"/bin/bash", HealthCheckScript, binary, check,
+			},
+		},
+	}
+}
+
+// GetHealthCheckLivenessProbe - liveness probe of an RPC service, does not depend on the DB
+func GetHealthCheckLivenessProbe(binary string, probes novav1beta1.Probes) *corev1.Probe {
+	return GetProbe(getHealthCheckHandler(binary, HealthCheckLiveness), probes.Liveness, healthCheckLivenessDefaults)
+}
+
+// GetHealthCheckReadinessProbe - readiness probe of an RPC service running the health check
+func GetHealthCheckReadinessProbe(binary string, probes novav1beta1.Probes) *corev1.Probe {
+	return GetProbe(getHealthCheckHandler(binary, HealthCheckReadiness), probes.Readiness, healthCheckReadinessDefaults)
+}
+
+// GetHealthCheckStartupProbe - startup probe of an RPC service running the health check
+func GetHealthCheckStartupProbe(binary string, probes novav1beta1.Probes) *corev1.Probe {
+	return GetProbe(getHealthCheckHandler(binary, HealthCheckReadiness), probes.Startup, healthCheckStartupDefaults)
+}
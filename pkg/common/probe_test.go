@@ -0,0 +1,146 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetProbe(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"true"}}}
+	defaults := novav1beta1.Probe{InitialDelaySeconds: 1, PeriodSeconds: 2, TimeoutSeconds: 3, FailureThreshold: 4}
+
+	// zero values use the defaults
+	probe := GetProbe(handler, novav1beta1.Probe{}, defaults)
+	assert.Equal(&corev1.Probe{Handler: handler, InitialDelaySeconds: 1, PeriodSeconds: 2, TimeoutSeconds: 3, FailureThreshold: 4}, probe)
+
+	probe = GetProbe(handler, novav1beta1.Probe{PeriodSeconds: 20, FailureThreshold: 40}, defaults)
+	assert.Equal(&corev1.Probe{Handler: handler, InitialDelaySeconds: 1, PeriodSeconds: 20, TimeoutSeconds: 3, FailureThreshold: 40}, probe)
+}
+
+func TestGetHealthCheckProbes(t *testing.T) {
+	assert := assert.New(t)
+
+	probes := novav1beta1.Probes{Liveness: novav1beta1.Probe{FailureThreshold: 5}}
+
+	// the liveness probe does not run the heartbeat query against the DB
+	liveness := GetHealthCheckLivenessProbe("nova-conductor", probes)
+	assert.Equal([]string{"/bin/bash", HealthCheckScript, "nova-conductor", HealthCheckLiveness}, liveness.Exec.Command)
+	assert.Equal(healthCheckLivenessDefaults.PeriodSeconds, liveness.PeriodSeconds)
+	assert.Equal(int32(5), liveness.FailureThreshold)
+
+	readiness := GetHealthCheckReadinessProbe("nova-conductor", probes)
+	assert.Equal([]string{"/bin/bash", HealthCheckScript, "nova-conductor", HealthCheckReadiness}, readiness.Exec.Command)
+	assert.Equal(healthCheckReadinessDefaults.FailureThreshold, readiness.FailureThreshold)
+
+	startup := GetHealthCheckStartupProbe("nova-conductor", probes)
+	assert.Equal([]string{"/bin/bash", HealthCheckScript, "nova-conductor", HealthCheckReadiness}, startup.Exec.Command)
+	assert.Equal(healthCheckStartupDefaults.FailureThreshold, startup.FailureThreshold)
+}
+
+// /proc/net/tcp line of a socket, the port of the remote address and the state are in hex
+func procNetTCPLine(remotePort int, state string, inode int) string {
+	return fmt.Sprintf("   0: 0100007F:A1B2 0100007F:%04X %s 00000000:00000000 00:00000000 00000000  1000        0 %d 1 0000000000000000 20 4 30 10 -1\n",
+		remotePort, state, inode)
+}
+
+func TestHealthCheckScript(t *testing.T) {
+	assert := assert.New(t)
+
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	script, err := filepath.Abs("../../templates/common/healthcheck.sh")
+	assert.NoError(err)
+
+	header := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	tests := []struct {
+		name      string
+		running   bool
+		tcp       string
+		tcp6      string
+		heartbeat bool
+		check     string
+		healthy   bool
+	}{
+		{"not running", false, "", "", true, HealthCheckLiveness, false},
+		{"no connection", true, "", "", true, HealthCheckLiveness, false},
+		{"connected to 5672", true, procNetTCPLine(5672, "01", 4242), "", true, HealthCheckLiveness, true},
+		{"connected to 5671 over IPv6", true, "", procNetTCPLine(5671, "01", 4242), true, HealthCheckLiveness, true},
+		{"connected to the DB only", true, procNetTCPLine(3306, "01", 4242), "", true, HealthCheckLiveness, false},
+		{"connecting to 5672", true, procNetTCPLine(5672, "02", 4242), "", true, HealthCheckLiveness, false},
+		{"connection of another process", true, procNetTCPLine(5672, "01", 1111), "", true, HealthCheckLiveness, false},
+		// the heartbeat is only checked for readiness
+		{"old heartbeat alive", true, procNetTCPLine(5672, "01", 4242), "", false, HealthCheckLiveness, true},
+		{"old heartbeat not ready", true, procNetTCPLine(5672, "01", 4242), "", false, HealthCheckReadiness, false},
+		{"ready", true, procNetTCPLine(5672, "01", 4242), "", true, HealthCheckReadiness, true},
+		{"disconnected not ready", true, procNetTCPLine(3306, "01", 4242), "", true, HealthCheckReadiness, false},
+		{"readiness by default", true, procNetTCPLine(5672, "01", 4242), "", false, "", false},
+		{"unknown check", true, procNetTCPLine(5672, "01", 4242), "", true, "startup", false},
+	}
+	for _, test := range tests {
+		dir, err := ioutil.TempDir("", "healthcheck")
+		assert.NoError(err)
+		defer os.RemoveAll(dir)
+
+		// pgrep finds the pid 100 of the service, python3 runs the heartbeat query
+		bin := filepath.Join(dir, "bin")
+		assert.NoError(os.MkdirAll(bin, 0755))
+		pids := ""
+		if test.running {
+			pids = "100"
+		}
+		assert.NoError(ioutil.WriteFile(filepath.Join(bin, "pgrep"), []byte("#!/bin/sh\necho "+pids+"\n"), 0755))
+		heartbeatExit := "0"
+		if !test.heartbeat {
+			heartbeatExit = "1"
+		}
+		assert.NoError(ioutil.WriteFile(filepath.Join(bin, "python3"), []byte("#!/bin/sh\ncat >/dev/null\nexit "+heartbeatExit+"\n"), 0755))
+
+		// the service process has a socket with the inode 4242
+		proc := filepath.Join(dir, "proc")
+		assert.NoError(os.MkdirAll(filepath.Join(proc, "100", "fd"), 0755))
+		assert.NoError(os.MkdirAll(filepath.Join(proc, "net"), 0755))
+		assert.NoError(os.Symlink("socket:[4242]", filepath.Join(proc, "100", "fd", "3")))
+		assert.NoError(os.Symlink("/dev/null", filepath.Join(proc, "100", "fd", "0")))
+		assert.NoError(ioutil.WriteFile(filepath.Join(proc, "net", "tcp"), []byte(header+test.tcp), 0644))
+		assert.NoError(ioutil.WriteFile(filepath.Join(proc, "net", "tcp6"), []byte(header+test.tcp6), 0644))
+
+		args := []string{script, "nova-conductor"}
+		if test.check != "" {
+			args = append(args, test.check)
+		}
+		cmd := exec.Command("bash", args...)
+		cmd.Env = append(os.Environ(), "PATH="+bin+":"+os.Getenv("PATH"), "PROC_ROOT="+proc)
+		out, err := cmd.CombinedOutput()
+		assert.Equal(test.healthy, err == nil, "%s: %s", test.name, out)
+	}
+
+	// the binary is required
+	assert.Error(exec.Command("bash", script).Run())
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GetRolloutSecret - secret for the next service of an ordered rollout, the previous secret
+// is kept until the current service rolled out with the new one
+func GetRolloutSecret(secret string, previousSecret string, rolledOut bool) string {
+	if !rolledOut && previousSecret != "" {
+		return previousSecret
+	}
+	return secret
+}
+
+// PodTemplateUsesSecret - returns true if a container or volume of the pod template references the secret
+func PodTemplateUsesSecret(template *corev1.PodTemplateSpec, secretName string) bool {
+	for _, volume := range template.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == secretName {
+			return true
+		}
+	}
+
+	containers := append(template.Spec.InitContainers, template.Spec.Containers...)
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == secretName {
+				return true
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil && envFrom.SecretRef.Name == secretName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsDeploymentRolledOut - returns true if the deployment references the secret and all replicas got updated and are available
+func IsDeploymentRolledOut(r ReconcilerCommon, name string, namespace string, secretName string) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, deployment)
+	if err != nil && k8s_errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if !PodTemplateUsesSecret(&deployment.Spec.Template, secretName) {
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas, nil
+}
+
+// IsStatefulSetRolledOut - returns true if the statefulset references the secret and all replicas got updated and are ready
+func IsStatefulSetRolledOut(r ReconcilerCommon, name string, namespace string, secretName string) (bool, error) {
+	statefulset := &appsv1.StatefulSet{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, statefulset)
+	if err != nil && k8s_errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if !PodTemplateUsesSecret(&statefulset.Spec.Template, secretName) {
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if statefulset.Spec.Replicas != nil {
+		replicas = *statefulset.Spec.Replicas
+	}
+	return statefulset.Status.ObservedGeneration >= statefulset.Generation &&
+		statefulset.Status.CurrentRevision == statefulset.Status.UpdateRevision &&
+		statefulset.Status.UpdatedReplicas == replicas &&
+		statefulset.Status.ReadyReplicas == replicas, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestPodTemplate - pod template reading the DB password from the secret
+func newTestPodTemplate(secretName string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "nova",
+				Env: []corev1.EnvVar{{
+					Name: "DatabasePassword",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Key:                  "DatabasePassword",
+						},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func TestGetRolloutSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("nova-secret-v2", GetRolloutSecret("nova-secret-v2", "nova-secret", true))
+	// the next service keeps the previous secret until the current one rolled out
+	assert.Equal("nova-secret", GetRolloutSecret("nova-secret-v2", "nova-secret", false))
+	// no rotation in progress on the initial deployment
+	assert.Equal("nova-secret", GetRolloutSecret("nova-secret", "", false))
+}
+
+func TestPodTemplateUsesSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	template := newTestPodTemplate("nova-secret")
+	assert.True(PodTemplateUsesSecret(&template, "nova-secret"))
+	assert.False(PodTemplateUsesSecret(&template, "nova-secret-v2"))
+
+	// init containers, envFrom and volumes
+	template = corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env-secret"}}}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "secret",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "volume-secret"}},
+			}},
+		},
+	}
+	assert.True(PodTemplateUsesSecret(&template, "env-secret"))
+	assert.True(PodTemplateUsesSecret(&template, "volume-secret"))
+	assert.False(PodTemplateUsesSecret(&template, "nova-secret"))
+}
+
+func TestIsDeploymentRolledOut(t *testing.T) {
+	assert := assert.New(t)
+
+	rolledOut := appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}
+	tests := []struct {
+		name     string
+		secret   string
+		status   appsv1.DeploymentStatus
+		expected bool
+	}{
+		{"rolled out", "nova-secret-v2", rolledOut, true},
+		{"old secret in the template", "nova-secret", rolledOut, false},
+		{"stale observedGeneration", "nova-secret-v2", appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}, false},
+		{"updatedReplicas < replicas", "nova-secret-v2", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3}, false},
+		{"old pods not terminated yet", "nova-secret-v2", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3}, false},
+		{"updated pods not available", "nova-secret-v2", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}, false},
+	}
+	for _, test := range tests {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack", Generation: 2},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(3),
+				Template: newTestPodTemplate("nova-secret-v2"),
+			},
+			Status: test.status,
+		}
+		r := newTestReconciler(deployment)
+
+		result, err := IsDeploymentRolledOut(r, "nova-api", "openstack", test.secret)
+		assert.NoError(err)
+		assert.Equal(test.expected, result, test.name)
+	}
+
+	// not deployed yet
+	result, err := IsDeploymentRolledOut(newTestReconciler(), "nova-api", "openstack", "nova-secret")
+	assert.NoError(err)
+	assert.False(result)
+}
+
+func TestIsStatefulSetRolledOut(t *testing.T) {
+	assert := assert.New(t)
+
+	rolledOut := appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "r2", UpdateRevision: "r2"}
+	tests := []struct {
+		name     string
+		secret   string
+		status   appsv1.StatefulSetStatus
+		expected bool
+	}{
+		{"rolled out", "nova-secret-v2", rolledOut, true},
+		{"old secret in the template", "nova-secret", rolledOut, false},
+		{"stale observedGeneration", "nova-secret-v2", appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "r2", UpdateRevision: "r2"}, false},
+		{"updatedReplicas < replicas", "nova-secret-v2", appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 2, CurrentRevision: "r2", UpdateRevision: "r2"}, false},
+		{"rollout in progress", "nova-secret-v2", appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "r1", UpdateRevision: "r2"}, false},
+		{"updated pods not ready", "nova-secret-v2", appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 2, UpdatedReplicas: 3, CurrentRevision: "r2", UpdateRevision: "r2"}, false},
+	}
+	for _, test := range tests {
+		statefulset := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "nova-super-conductor", Namespace: "openstack", Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: int32Ptr(3),
+				Template: newTestPodTemplate("nova-secret-v2"),
+			},
+			Status: test.status,
+		}
+		r := newTestReconciler(statefulset)
+
+		result, err := IsStatefulSetRolledOut(r, "nova-super-conductor", "openstack", test.secret)
+		assert.NoError(err)
+		assert.Equal(test.expected, result, test.name)
+	}
+
+	// not deployed yet
+	result, err := IsStatefulSetRolledOut(newTestReconciler(), "nova-super-conductor", "openstack", "nova-secret")
+	assert.NoError(err)
+	assert.False(result)
+}
@@ -18,50 +18,332 @@ package common
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
 	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // BITSIZE
 const (
 	BITSIZE int = 4096
+	// PasswordLength - number of random bytes of a generated password
+	PasswordLength int = 16
 )
 
-// GetSecretsFromCR - get all parameters ending with "Secret" from Spec to verify they exist, add the hash to env and status
+// SecretIndexField - field index of the secrets referenced in the Spec of a CR
+const SecretIndexField = ".spec.secrets"
+
+// GetPasswordSelectors - returns the password selectors with the default key names for unset fields
+func GetPasswordSelectors(selectors novav1beta1.PasswordSelector) novav1beta1.PasswordSelector {
+	if selectors.Database == "" {
+		selectors.Database = DatabasePasswordKey
+	}
+	if selectors.DatabaseAdmin == "" {
+		selectors.DatabaseAdmin = "DbRootPassword"
+	}
+	if selectors.Service == "" {
+		selectors.Service = "NovaKeystoneAuthPassword"
+	}
+	if selectors.Placement == "" {
+		selectors.Placement = "PlacementKeystoneAuthPassword"
+	}
+	if selectors.Neutron == "" {
+		selectors.Neutron = "NeutronKeystoneAuthPassword"
+	}
+	if selectors.TransportURL == "" {
+		selectors.TransportURL = "TransportUrl"
+	}
+	if selectors.Metadata == "" {
+		selectors.Metadata = "MetadataProxySharedSecret"
+	}
+	if selectors.ApplicationCredentialID == "" {
+		selectors.ApplicationCredentialID = "ApplicationCredentialID"
+	}
+	if selectors.ApplicationCredentialSecret == "" {
+		selectors.ApplicationCredentialSecret = "ApplicationCredentialSecret"
+	}
+	return selectors
+}
+
+// GetSecretRequiredKeys - required keys per Spec secret parameter
+func GetSecretRequiredKeys(selectors novav1beta1.PasswordSelector) map[string][]string {
+	selectors = GetPasswordSelectors(selectors)
+	return map[string][]string{
+		"novaSecret":          {selectors.Database, selectors.Service},
+		"databaseAdminSecret": {selectors.DatabaseAdmin},
+		"placementSecret":     {selectors.Placement},
+		"neutronSecret":       {selectors.Neutron},
+		"transportURLSecret":  {selectors.TransportURL},
+		// separate notifications bus of the api and cell services
+		"notificationsTransportURLSecret":  {selectors.TransportURL},
+		"notifications.transportURLSecret": {selectors.TransportURL},
+		// optional, the nova service authenticates with the application credential instead of the password
+		"applicationCredentialSecret": {selectors.ApplicationCredentialID, selectors.ApplicationCredentialSecret},
+	}
+}
+
+// SecretMissingKeyError - a referenced secret does not contain a required key
+type SecretMissingKeyError struct {
+	SecretName string
+	Key        string
+}
+
+func (e *SecretMissingKeyError) Error() string {
+	return fmt.Sprintf("Secret %s is missing required key %s", e.SecretName, e.Key)
+}
+
+// IsSecretMissingKey - returns true if the error reports a secret with a missing key
+func IsSecretMissingKey(err error) bool {
+	_, ok := err.(*SecretMissingKeyError)
+	return ok
+}
+
+// SecretNotSetError - a secret required by another Spec parameter is not set
+type SecretNotSetError struct {
+	Parameter   string
+	RequiredFor string
+}
+
+func (e *SecretNotSetError) Error() string {
+	return fmt.Sprintf("%s is required for %s", e.Parameter, e.RequiredFor)
+}
+
+// IsSecretInvalid - returns true if the error reports a secret with a missing key or a required secret which is not set
+func IsSecretInvalid(err error) bool {
+	if _, ok := err.(*SecretNotSetError); ok {
+		return true
+	}
+	return IsSecretMissingKey(err)
+}
+
+// GetSecretCondition - returns the status condition for the referenced secrets
+func GetSecretCondition(err error) novav1beta1.Condition {
+	condition := novav1beta1.Condition{
+		Type:    novav1beta1.SecretsCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  novav1beta1.SecretsValidReason,
+		Message: "Secrets contain all required keys",
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = novav1beta1.SecretMissingKeyReason
+		condition.Message = err.Error()
+		if _, ok := err.(*SecretNotSetError); ok {
+			condition.Reason = novav1beta1.SecretNotSetReason
+		}
+	}
+	return condition
+}
+
+// HandleSecretError - a secret missing a required key, or a required secret which is not set, gets reported
+// in the Secrets condition and the reconcile stops without rolling the pods, the secret watch or the CR update
+// triggers the next one. Other errors requeue.
+func HandleSecretError(r ReconcilerCommon, obj runtime.Object, conditions *[]novav1beta1.Condition, err error) (ctrl.Result, error) {
+	if !IsSecretInvalid(err) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+
+	r.GetLogger().Info(err.Error())
+	if condErr := UpdateStatusCondition(r, obj, conditions, GetSecretCondition(err)); condErr != nil {
+		return ctrl.Result{}, condErr
+	}
+	return ctrl.Result{}, nil
+}
+
+// GetSecretsFromCR - get all parameters ending with "Secret" from Spec to verify they exist and contain the
+// required keys, add the hash to env and status
 func GetSecretsFromCR(r ReconcilerCommon, obj runtime.Object, namespace string, spec interface{}, envVars *map[string]util.EnvSetter) ([]novav1beta1.Hash, error) {
 	hashes := []novav1beta1.Hash{}
+
+	secretParameters := getSecretParameters(spec)
+	requiredKeys := GetSecretRequiredKeys(getPasswordSelectors(spec))
+	// sorted to always report the same missing key first
+	params := make([]string, 0, len(secretParameters))
+	for param := range secretParameters {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		_, hash, err := GetSecret(r.GetClient(), secretParameters[param], namespace, requiredKeys[param]...)
+		if err != nil {
+			return nil, err
+		}
+
+		// add hash to envVars
+		(*envVars)[param] = util.EnvValue(hash)
+		hashes = append(hashes, novav1beta1.Hash{Name: param, Hash: hash})
+	}
+
+	// DB CA certificate if DB TLS is enabled
+	tls := getDatabaseTLS(spec)
+	hash, err := GetDatabaseCASecret(r.GetClient(), tls, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if hash != "" {
+		(*envVars)[tls.CASecret] = util.EnvValue(hash)
+		hashes = append(hashes, novav1beta1.Hash{Name: "databaseTLS.caSecret", Hash: hash})
+	}
+
+	return hashes, nil
+}
+
+// getSecretParameters - get all string parameters ending with "Secret" from Spec with their value, including the
+// ones of nested structs and lists, e.g. notifications.transportURLSecret or cells[0].transportURLSecret
+func getSecretParameters(spec interface{}) map[string]string {
+	secretParameters := make(map[string]string)
 	specParameters := make(map[string]interface{})
 	inrec, _ := json.Marshal(spec)
 	json.Unmarshal(inrec, &specParameters)
 
-	for param, value := range specParameters {
-		if strings.HasSuffix(param, "Secret") {
-			_, hash, err := GetSecret(r.GetClient(), fmt.Sprintf("%v", value), namespace)
-			if err != nil {
-				return nil, err
+	addSecretParameters(secretParameters, "", specParameters)
+
+	return secretParameters
+}
+
+// addSecretParameters - add the string parameters ending with "Secret" of the map to the secret parameters
+func addSecretParameters(secretParameters map[string]string, prefix string, parameters map[string]interface{}) {
+	for param, value := range parameters {
+		switch param {
+		// key names of the credentials, not secrets
+		case "passwordSelectors":
+			continue
+		// the CA secret is only required with a TLS mode, see getDatabaseTLS
+		case "databaseTLS":
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if strings.HasSuffix(param, "Secret") {
+				secretParameters[prefix+param] = v
+			}
+		case map[string]interface{}:
+			addSecretParameters(secretParameters, prefix+param+".", v)
+		case []interface{}:
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					addSecretParameters(secretParameters, fmt.Sprintf("%s%s[%d].", prefix, param, i), m)
+				}
 			}
+		}
+	}
+}
+
+// getPasswordSelectors - get the password selectors from Spec
+func getPasswordSelectors(spec interface{}) novav1beta1.PasswordSelector {
+	specParameters := struct {
+		PasswordSelectors novav1beta1.PasswordSelector `json:"passwordSelectors"`
+	}{}
+	inrec, _ := json.Marshal(spec)
+	json.Unmarshal(inrec, &specParameters)
+
+	return specParameters.PasswordSelectors
+}
+
+// getDatabaseTLS - get the DB TLS settings from Spec
+func getDatabaseTLS(spec interface{}) novav1beta1.DatabaseTLS {
+	specParameters := struct {
+		DatabaseTLS novav1beta1.DatabaseTLS `json:"databaseTLS"`
+	}{}
+	inrec, _ := json.Marshal(spec)
+	json.Unmarshal(inrec, &specParameters)
 
-			// add hash to envVars
-			(*envVars)[param] = util.EnvValue(hash)
-			hashes = append(hashes, novav1beta1.Hash{Name: param, Hash: hash})
+	return specParameters.DatabaseTLS
+}
+
+// SecretIndexFunc - field indexer returning the names of all secrets referenced in the Spec of a CR
+func SecretIndexFunc(obj runtime.Object) []string {
+	cr := struct {
+		Spec map[string]interface{} `json:"spec"`
+	}{}
+	inrec, _ := json.Marshal(obj)
+	json.Unmarshal(inrec, &cr)
+
+	secrets := []string{}
+	for _, value := range getSecretParameters(cr.Spec) {
+		if value != "" {
+			secrets = append(secrets, value)
 		}
 	}
+	if tls := getDatabaseTLS(cr.Spec); tls.CASecret != "" {
+		secrets = append(secrets, tls.CASecret)
+	}
 
-	return hashes, nil
+	return secrets
+}
+
+// SetupSecretIndex - index the CR type by the secrets referenced in its Spec
+func SetupSecretIndex(mgr ctrl.Manager, obj runtime.Object) error {
+	return mgr.GetFieldIndexer().IndexField(context.Background(), obj, SecretIndexField, SecretIndexFunc)
+}
+
+// GetSecretWatchFn - map a secret to reconcile requests for all CRs of the list type referencing it
+func GetSecretWatchFn(r ReconcilerCommon, list runtime.Object) handler.ToRequestsFunc {
+	return getIndexWatchFn(r, list, SecretIndexField, "Secret")
+}
+
+// getIndexWatchFn - map an object to reconcile requests for all CRs of the list type referencing it in the field index
+func getIndexWatchFn(r ReconcilerCommon, list runtime.Object, indexField string, kind string) handler.ToRequestsFunc {
+	return handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+		result := []reconcile.Request{}
+
+		crList := list.DeepCopyObject()
+		listOpts := []client.ListOption{
+			client.InNamespace(obj.Meta.GetNamespace()),
+			client.MatchingFields{indexField: obj.Meta.GetName()},
+		}
+		if err := r.GetClient().List(context.Background(), crList, listOpts...); err != nil {
+			r.GetLogger().Error(err, fmt.Sprintf("Unable to retrieve CRs referencing %s", kind), kind+".Name", obj.Meta.GetName())
+			return nil
+		}
+
+		items, err := meta.ExtractList(crList)
+		if err != nil {
+			r.GetLogger().Error(err, "Unable to extract CRs from list")
+			return nil
+		}
+
+		for _, item := range items {
+			cr, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+			name := client.ObjectKey{
+				Namespace: cr.GetNamespace(),
+				Name:      cr.GetName(),
+			}
+			r.GetLogger().Info(fmt.Sprintf("%s %s referenced by CR %s", kind, obj.Meta.GetName(), cr.GetName()))
+			result = append(result, reconcile.Request{NamespacedName: name})
+		}
+
+		if len(result) > 0 {
+			return result
+		}
+		return nil
+	})
 }
 
-// GetSecret -
-func GetSecret(c client.Client, secretName string, secretNamespace string) (*corev1.Secret, string, error) {
+// GetSecret - get the secret and verify it contains the required keys
+func GetSecret(c client.Client, secretName string, secretNamespace string, requiredKeys ...string) (*corev1.Secret, string, error) {
 	secret := &corev1.Secret{}
 
 	err := c.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: secretNamespace}, secret)
@@ -69,6 +351,12 @@ func GetSecret(c client.Client, secretName string, secretNamespace string) (*cor
 		return nil, "", err
 	}
 
+	for _, key := range requiredKeys {
+		if _, ok := secret.Data[key]; !ok {
+			return nil, "", &SecretMissingKeyError{SecretName: secretName, Key: key}
+		}
+	}
+
 	secretHash, err := util.ObjectHash(secret)
 	if err != nil {
 		return nil, "", fmt.Errorf("error calculating configuration hash: %v", err)
@@ -126,3 +414,12 @@ func SSHKeySecret(name string, namespace string, labels map[string]string) (*cor
 	}
 	return secret, nil
 }
+
+// GeneratePassword - random password of hex encoded random bytes
+func GeneratePassword(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
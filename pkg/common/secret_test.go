@@ -0,0 +1,220 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testReconciler - ReconcilerCommon backed by a fake client
+type testReconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func (r *testReconciler) GetClient() client.Client {
+	return r.client
+}
+
+func (r *testReconciler) GetLogger() logr.Logger {
+	return ctrl.Log.WithName("test")
+}
+
+func (r *testReconciler) GetScheme() *runtime.Scheme {
+	return r.scheme
+}
+
+func newTestReconciler(objs ...runtime.Object) *testReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = novav1beta1.AddToScheme(scheme)
+	return &testReconciler{client: fake.NewFakeClientWithScheme(scheme, objs...), scheme: scheme}
+}
+
+func TestGetSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newTestReconciler(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-secret", Namespace: "openstack"},
+		Data: map[string][]byte{
+			"DatabasePassword":         []byte("foo"),
+			"NovaKeystoneAuthPassword": []byte("bar"),
+		},
+	})
+
+	tests := []struct {
+		name         string
+		requiredKeys []string
+		missingKey   bool
+		notFound     bool
+	}{
+		{"nova-secret", nil, false, false},
+		{"nova-secret", []string{"DatabasePassword", "NovaKeystoneAuthPassword"}, false, false},
+		{"nova-secret", []string{"DatabasePassword", "TransportUrl"}, true, false},
+		{"missing-secret", []string{"DatabasePassword"}, false, true},
+	}
+	for _, test := range tests {
+		secret, hash, err := GetSecret(r.GetClient(), test.name, "openstack", test.requiredKeys...)
+		assert.Equal(test.missingKey, IsSecretMissingKey(err), test.requiredKeys)
+		assert.Equal(test.notFound, k8s_errors.IsNotFound(err), test.name)
+		if test.missingKey || test.notFound {
+			assert.Nil(secret)
+			assert.Empty(hash)
+		} else {
+			assert.NoError(err)
+			assert.NotEmpty(hash)
+		}
+	}
+
+	_, _, err := GetSecret(r.GetClient(), "nova-secret", "openstack", "TransportUrl")
+	assert.Equal("Secret nova-secret is missing required key TransportUrl", err.Error())
+}
+
+func TestHandleSecretError(t *testing.T) {
+	assert := assert.New(t)
+
+	instance := &novav1beta1.NovaAPI{ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack"}}
+	r := newTestReconciler(instance)
+
+	// missing keys get reported in the Secrets condition without a requeue
+	result, err := HandleSecretError(r, instance, &instance.Status.Conditions, &SecretMissingKeyError{SecretName: "nova-secret", Key: "TransportUrl"})
+	assert.NoError(err)
+	assert.Equal(ctrl.Result{}, result)
+	assert.Len(instance.Status.Conditions, 1)
+	assert.Equal(novav1beta1.SecretsCondition, instance.Status.Conditions[0].Type)
+	assert.Equal(corev1.ConditionFalse, instance.Status.Conditions[0].Status)
+	assert.Equal(novav1beta1.SecretMissingKeyReason, instance.Status.Conditions[0].Reason)
+
+	// other errors requeue
+	notFound := k8s_errors.NewNotFound(corev1.Resource("secrets"), "nova-secret")
+	result, err = HandleSecretError(r, instance, &instance.Status.Conditions, notFound)
+	assert.Equal(notFound, err)
+	assert.Equal(ctrl.Result{RequeueAfter: time.Second * 10}, result)
+
+	assert.Equal(corev1.ConditionTrue, GetSecretCondition(nil).Status)
+}
+
+func TestPasswordSelectors(t *testing.T) {
+	assert := assert.New(t)
+
+	defaults := GetPasswordSelectors(novav1beta1.PasswordSelector{})
+	tests := []struct {
+		selector string
+		expected string
+	}{
+		{defaults.Database, "DatabasePassword"},
+		{defaults.Service, "NovaKeystoneAuthPassword"},
+		{defaults.Placement, "PlacementKeystoneAuthPassword"},
+		{defaults.Neutron, "NeutronKeystoneAuthPassword"},
+		{defaults.TransportURL, "TransportUrl"},
+		{defaults.Metadata, "MetadataProxySharedSecret"},
+		{defaults.ApplicationCredentialID, "ApplicationCredentialID"},
+		{defaults.ApplicationCredentialSecret, "ApplicationCredentialSecret"},
+	}
+	for _, test := range tests {
+		assert.Equal(test.expected, test.selector)
+	}
+
+	// set selectors are kept
+	selectors := GetPasswordSelectors(novav1beta1.PasswordSelector{Service: "NovaPassword", TransportURL: "url"})
+	assert.Equal("NovaPassword", selectors.Service)
+	assert.Equal("url", selectors.TransportURL)
+	assert.Equal("PlacementKeystoneAuthPassword", selectors.Placement)
+}
+
+func TestSecretRequiredKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	requiredKeys := GetSecretRequiredKeys(novav1beta1.PasswordSelector{})
+	assert.Equal([]string{"DatabasePassword", "NovaKeystoneAuthPassword"}, requiredKeys["novaSecret"])
+	assert.Equal([]string{"PlacementKeystoneAuthPassword"}, requiredKeys["placementSecret"])
+	assert.Equal([]string{"ApplicationCredentialID", "ApplicationCredentialSecret"}, requiredKeys["applicationCredentialSecret"])
+	assert.Nil(requiredKeys["unknownSecret"])
+
+	requiredKeys = GetSecretRequiredKeys(novav1beta1.PasswordSelector{Service: "NovaPassword", TransportURL: "url"})
+	assert.Equal([]string{"DatabasePassword", "NovaPassword"}, requiredKeys["novaSecret"])
+	assert.Equal([]string{"url"}, requiredKeys["transportURLSecret"])
+	assert.Equal([]string{"url"}, requiredKeys["notificationsTransportURLSecret"])
+
+	// selectors get read from the Spec of the CR
+	spec := novav1beta1.NovaAPISpec{PasswordSelectors: novav1beta1.PasswordSelector{Service: "NovaPassword"}}
+	assert.Equal("NovaPassword", getPasswordSelectors(spec).Service)
+}
+
+func TestGetSecretParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := novav1beta1.NovaSpec{
+		NovaSecret:         "nova-secret",
+		TransportURLSecret: "transport-secret",
+		GenerateNovaSecret: true,
+		Notifications:      novav1beta1.Notifications{Enabled: true, TransportURLSecret: "notifications-secret"},
+		PasswordSelectors:  novav1beta1.PasswordSelector{ApplicationCredentialSecret: "AppCredSecret"},
+		DatabaseTLS:        novav1beta1.DatabaseTLS{CASecret: "ca-secret"},
+		Cells:              []novav1beta1.Cell{{Name: "cell1", TransportURLSecret: "cell1-transport-secret"}},
+	}
+
+	// only string parameters, the selectors are key names and the CA secret depends on the TLS mode
+	assert.Equal(map[string]string{
+		"novaSecret":                       "nova-secret",
+		"transportURLSecret":               "transport-secret",
+		"notifications.transportURLSecret": "notifications-secret",
+		"cells[0].transportURLSecret":      "cell1-transport-secret",
+	}, getSecretParameters(spec))
+
+	requiredKeys := GetSecretRequiredKeys(novav1beta1.PasswordSelector{})
+	assert.Equal([]string{"TransportUrl"}, requiredKeys["notifications.transportURLSecret"])
+}
+
+func TestSecretIndexFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	nova := &novav1beta1.Nova{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova", Namespace: "openstack"},
+		Spec: novav1beta1.NovaSpec{
+			NovaSecret:         "nova-secret",
+			GenerateNovaSecret: true,
+			Notifications:      novav1beta1.Notifications{TransportURLSecret: "notifications-secret"},
+			DatabaseTLS:        novav1beta1.DatabaseTLS{Mode: "require", CASecret: "ca-secret"},
+			Cells:              []novav1beta1.Cell{{Name: "cell1", TransportURLSecret: "cell1-transport-secret"}},
+		},
+	}
+	assert.ElementsMatch([]string{"nova-secret", "notifications-secret", "cell1-transport-secret", "ca-secret"}, SecretIndexFunc(nova))
+
+	// unset secrets are not indexed
+	api := &novav1beta1.NovaAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-api", Namespace: "openstack"},
+		Spec: novav1beta1.NovaAPISpec{
+			NovaSecret:                      "nova-secret",
+			NotificationsTransportURLSecret: "notifications-secret",
+		},
+	}
+	assert.ElementsMatch([]string{"nova-secret", "notifications-secret"}, SecretIndexFunc(api))
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -62,3 +63,43 @@ func UpdateStatusHash(r ReconcilerCommon, obj runtime.Object, hashes *[]novav1be
 	}
 	return nil
 }
+
+// UpdateStatusCondition - update/set a condition in CR status
+func UpdateStatusCondition(r ReconcilerCommon, obj runtime.Object, conditions *[]novav1beta1.Condition, newCondition novav1beta1.Condition) error {
+	update := true
+
+	// is there already an existing condition of this type
+	exists := false
+	for i := 0; i < len(*conditions); i++ {
+		if (*conditions)[i].Type == newCondition.Type {
+			c := &(*conditions)[i]
+			if c.Status == newCondition.Status && c.Reason == newCondition.Reason && c.Message == newCondition.Message {
+				update = false
+			} else {
+				r.GetLogger().Info(fmt.Sprintf("Condition %s changed - status: %s reason: %s", newCondition.Type, newCondition.Status, newCondition.Reason))
+				if c.Status != newCondition.Status {
+					c.LastTransitionTime = metav1.Now()
+				}
+				c.Status = newCondition.Status
+				c.Reason = newCondition.Reason
+				c.Message = newCondition.Message
+			}
+			exists = true
+			break
+		}
+	}
+	// add condition entry for new type
+	if !exists {
+		r.GetLogger().Info(fmt.Sprintf("New condition %s added to status - status: %s reason: %s", newCondition.Type, newCondition.Status, newCondition.Reason))
+		newCondition.LastTransitionTime = metav1.Now()
+		*conditions = append(*conditions, newCondition)
+	}
+
+	// update status if required
+	if update {
+		if err := r.GetClient().Status().Update(context.TODO(), obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package common
 
 import (
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -111,3 +112,78 @@ func GetVolumeMounts() []corev1.VolumeMount {
 	}
 
 }
+
+// GetDatabaseTLSVolumes - volume with the DB CA certificate if DB TLS is enabled
+func GetDatabaseTLSVolumes(tls novav1beta1.DatabaseTLS) []corev1.Volume {
+	if tls.Mode == "" {
+		return []corev1.Volume{}
+	}
+
+	return []corev1.Volume{
+		{
+			Name: "db-tls-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: tls.CASecret,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  DatabaseCAKey,
+							Path: DatabaseCAKey,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetDatabaseTLSVolumeMounts - DB CA certificate VolumeMount if DB TLS is enabled
+func GetDatabaseTLSVolumeMounts(tls novav1beta1.DatabaseTLS) []corev1.VolumeMount {
+	if tls.Mode == "" {
+		return []corev1.VolumeMount{}
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      "db-tls-ca",
+			MountPath: DatabaseCADir,
+			ReadOnly:  true,
+		},
+	}
+}
+
+// GetPolicyVolumes - volume with the validated policy overrides if a policy is set
+func GetPolicyVolumes(name string, policy novav1beta1.Policy) []corev1.Volume {
+	if policy.Inline == "" && policy.ConfigMap == "" {
+		return []corev1.Volume{}
+	}
+
+	return []corev1.Volume{
+		{
+			Name: "policy",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: GetPolicyConfigMapName(name),
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetPolicyVolumeMounts - policy.yaml VolumeMount if a policy is set, changes roll the pods
+func GetPolicyVolumeMounts(policy novav1beta1.Policy) []corev1.VolumeMount {
+	if policy.Inline == "" && policy.ConfigMap == "" {
+		return []corev1.VolumeMount{}
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      "policy",
+			MountPath: PolicyPath,
+			SubPath:   PolicyFile,
+			ReadOnly:  true,
+		},
+	}
+}
@@ -16,6 +16,9 @@ limitations under the License.
 
 package nova
 
+// Databases - databases of the api level
+var Databases = []string{"nova_api", "nova_cell0"}
+
 const (
 	// AppLabel -
 	AppLabel = "nova"
@@ -25,6 +28,12 @@ const (
 	APIDatabase = "api"
 	// CellDatabase -
 	CellDatabase = "cell0"
+	// APIService - DB account name of nova-api
+	APIService = "api"
+	// SchedulerService - DB account name of nova-scheduler
+	SchedulerService = "scheduler"
+	// ConductorService - DB account name of nova-super-conductor
+	ConductorService = "conductor"
 	// DBSyncKollaConfig -
 	DBSyncKollaConfig = "/var/lib/config-data/merged/db-sync-config.json"
 )
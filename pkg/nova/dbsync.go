@@ -25,10 +25,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // DbSyncJob func
-func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme) *batchv1.Job {
+func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme, transportURLSecret string, novaSecret string) *batchv1.Job {
+	selectors := common.GetPasswordSelectors(cr.Spec.PasswordSelectors)
 
 	runAsUser := int64(0)
 
@@ -36,6 +38,10 @@ func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme) *batchv1.Job {
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(cr.Name)
 
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(cr.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(cr.Spec.DatabaseTLS)...)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-db-sync",
@@ -43,15 +49,19 @@ func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme) *batchv1.Job {
 			Labels:    common.GetLabels(cr.Name, AppLabel),
 		},
 		Spec: batchv1.JobSpec{
+			// keep failed pods and their logs, kubernetes retries with an exponential back-off
+			BackoffLimit: common.GetJobRetryLimit(cr.Spec.JobRetryLimit),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy:      "OnFailure",
+					RestartPolicy:      "Never",
 					ServiceAccountName: "nova",
+					PriorityClassName:  cr.Spec.PriorityClassName,
 					Volumes:            volumes,
 					Containers: []corev1.Container{
 						{
-							Name:  cr.Name + "-db-sync",
-							Image: cr.Spec.NovaAPIContainerImage,
+							Name:      cr.Name + "-db-sync",
+							Image:     cr.Spec.NovaAPIContainerImage,
+							Resources: cr.Spec.JobResources,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &runAsUser,
 							},
@@ -72,6 +82,10 @@ func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme) *batchv1.Job {
 									Name:  "DatabaseHost",
 									Value: cr.Spec.DatabaseHostname,
 								},
+								{
+									Name:  "DatabaseConnectionParams",
+									Value: common.GetDatabaseConnectionParams(cr.Spec.DatabaseTLS),
+								},
 								{
 									Name:  "CellDatabase",
 									Value: fmt.Sprintf("nova_%s", CellDatabase),
@@ -81,9 +95,9 @@ func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme) *batchv1.Job {
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
 											LocalObjectReference: corev1.LocalObjectReference{
-												Name: cr.Spec.NovaSecret,
+												Name: novaSecret,
 											},
-											Key: "DatabasePassword",
+											Key: selectors.Database,
 										},
 									},
 								},
@@ -100,13 +114,16 @@ func DbSyncJob(cr *novav1beta1.Nova, scheme *runtime.Scheme) *batchv1.Job {
 		DatabaseHost:       cr.Spec.DatabaseHostname,
 		CellDatabase:       fmt.Sprintf("%s_%s", DatabasePrefix, CellDatabase),
 		APIDatabase:        fmt.Sprintf("%s_%s", DatabasePrefix, APIDatabase),
-		TransportURLSecret: cr.Spec.TransportURLSecret,
-		NovaSecret:         cr.Spec.NovaSecret,
+		DatabaseTLS:        cr.Spec.DatabaseTLS,
+		TransportURLSecret: transportURLSecret,
+		NovaSecret:         novaSecret,
 		NeutronSecret:      cr.Spec.NeutronSecret,
 		PlacementSecret:    cr.Spec.PlacementSecret,
+		PasswordSelectors:  cr.Spec.PasswordSelectors,
 		VolumeMounts:       initVolumeMounts,
+		Resources:          cr.Spec.JobResources,
 	}
 	job.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
-
+	controllerutil.SetControllerReference(cr, job, scheme)
 	return job
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nova
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDbSyncJob(t *testing.T) {
+	assert := assert.New(t)
+
+	scheme := runtime.NewScheme()
+	_ = novav1beta1.AddToScheme(scheme)
+
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+	cr := &novav1beta1.Nova{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova", Namespace: "openstack"},
+		Spec: novav1beta1.NovaSpec{
+			JobResources:      resources,
+			PriorityClassName: "openstack-control-plane",
+		},
+	}
+
+	job := DbSyncJob(cr, scheme, "nova-transport", "nova-secret")
+	assert.Equal("nova-db-sync", job.Name)
+	assert.Equal("openstack-control-plane", job.Spec.Template.Spec.PriorityClassName)
+	assert.NotEmpty(job.Spec.Template.Spec.Containers)
+	for _, container := range job.Spec.Template.Spec.Containers {
+		assert.Equal(resources, container.Resources, container.Name)
+	}
+	assert.NotEmpty(job.Spec.Template.Spec.InitContainers)
+	for _, container := range job.Spec.Template.Spec.InitContainers {
+		assert.Equal(resources, container.Resources, container.Name)
+	}
+
+	// no requests or limits and the default priority if not set
+	job = DbSyncJob(&novav1beta1.Nova{ObjectMeta: cr.ObjectMeta}, scheme, "nova-transport", "nova-secret")
+	assert.Empty(job.Spec.Template.Spec.PriorityClassName)
+	for _, container := range append(job.Spec.Template.Spec.Containers, job.Spec.Template.Spec.InitContainers...) {
+		assert.Equal(corev1.ResourceRequirements{}, container.Resources, container.Name)
+	}
+}
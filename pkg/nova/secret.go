@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nova
+
+import (
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NovaSecret - NovaSecret with random db and keystone passwords and metadata proxy shared secret
+func NovaSecret(cr *novav1beta1.Nova) (*corev1.Secret, error) {
+	selectors := common.GetPasswordSelectors(cr.Spec.PasswordSelectors)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Spec.NovaSecret,
+			Namespace: cr.Namespace,
+			Labels:    common.GetLabels(cr.Name, AppLabel),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+
+	for _, key := range []string{selectors.Database, selectors.Service, selectors.Metadata} {
+		password, err := common.GeneratePassword(common.PasswordLength)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[key] = []byte(password)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nova
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNovaSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	cr := &novav1beta1.Nova{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova", Namespace: "openstack"},
+		Spec:       novav1beta1.NovaSpec{NovaSecret: "nova-secret"},
+	}
+
+	// default key names
+	secret, err := NovaSecret(cr)
+	assert.NoError(err)
+	assert.Equal("nova-secret", secret.Name)
+	assert.Equal("openstack", secret.Namespace)
+	assert.Len(secret.Data, 3)
+	for _, key := range []string{"DatabasePassword", "NovaKeystoneAuthPassword", "MetadataProxySharedSecret"} {
+		assert.NotEmpty(secret.Data[key], key)
+	}
+
+	// the keys follow the custom selectors
+	cr.Spec.PasswordSelectors = novav1beta1.PasswordSelector{
+		Database: "NovaDBPassword",
+		Service:  "NovaPassword",
+		Metadata: "MetadataSecret",
+	}
+	secret, err = NovaSecret(cr)
+	assert.NoError(err)
+	assert.Len(secret.Data, 3)
+	for _, key := range []string{"NovaDBPassword", "NovaPassword", "MetadataSecret"} {
+		assert.NotEmpty(secret.Data[key], key)
+	}
+
+	// random passwords, which differ per key and per secret
+	assert.NotEqual(secret.Data["NovaDBPassword"], secret.Data["NovaPassword"])
+	other, err := NovaSecret(cr)
+	assert.NoError(err)
+	assert.NotEqual(secret.Data["NovaDBPassword"], other.Data["NovaDBPassword"])
+}
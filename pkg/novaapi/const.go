@@ -27,4 +27,12 @@ const (
 	CellDatabase = "cell0"
 	// KollaConfig -
 	KollaConfig = "/var/lib/config-data/merged/nova-api-config.json"
+	// APIPort - port of the WSGI endpoint
+	APIPort = 8774
+	// WSGIDefaultProcesses - WSGI daemon processes without CPU limit
+	WSGIDefaultProcesses = 4
+	// WSGIDefaultThreads - threads per WSGI daemon process
+	WSGIDefaultThreads = 1
+	// WSGIDefaultTimeout - seconds httpd waits for I/O on a connection
+	WSGIDefaultTimeout = 60
 )
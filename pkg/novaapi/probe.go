@@ -0,0 +1,58 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novaapi
+
+import (
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	// livenessDefaults - restart after 30s of a failing API
+	livenessDefaults = novav1beta1.Probe{PeriodSeconds: 10, TimeoutSeconds: 5, FailureThreshold: 3}
+	// readinessDefaults - remove from the Service after 15s of a failing API
+	readinessDefaults = novav1beta1.Probe{PeriodSeconds: 5, TimeoutSeconds: 5, FailureThreshold: 3}
+	// startupDefaults - wait up to 5m for the first boot
+	startupDefaults = novav1beta1.Probe{PeriodSeconds: 10, TimeoutSeconds: 5, FailureThreshold: 30}
+)
+
+// getVersionDocumentHandler - HTTP GET of the version document served on / by the WSGI endpoint
+func getVersionDocumentHandler() corev1.Handler {
+	return corev1.Handler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path: "/",
+			Port: intstr.FromInt(APIPort),
+		},
+	}
+}
+
+// GetLivenessProbe - nova-api liveness probe
+func GetLivenessProbe(probes novav1beta1.Probes) *corev1.Probe {
+	return common.GetProbe(getVersionDocumentHandler(), probes.Liveness, livenessDefaults)
+}
+
+// GetReadinessProbe - nova-api readiness probe
+func GetReadinessProbe(probes novav1beta1.Probes) *corev1.Probe {
+	return common.GetProbe(getVersionDocumentHandler(), probes.Readiness, readinessDefaults)
+}
+
+// GetStartupProbe - nova-api startup probe
+func GetStartupProbe(probes novav1beta1.Probes) *corev1.Probe {
+	return common.GetProbe(getVersionDocumentHandler(), probes.Startup, startupDefaults)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novaapi
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetProbes(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := corev1.Handler{
+		HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(APIPort)},
+	}
+	probe := func(initialDelay int32, period int32, timeout int32, failure int32) *corev1.Probe {
+		return &corev1.Probe{
+			Handler:             handler,
+			InitialDelaySeconds: initialDelay,
+			PeriodSeconds:       period,
+			TimeoutSeconds:      timeout,
+			FailureThreshold:    failure,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		probes    novav1beta1.Probes
+		liveness  *corev1.Probe
+		readiness *corev1.Probe
+		startup   *corev1.Probe
+	}{
+		{"defaults", novav1beta1.Probes{}, probe(0, 10, 5, 3), probe(0, 5, 5, 3), probe(0, 10, 5, 30)},
+		// set fields override the defaults of the probe they are set on only
+		{"liveness override", novav1beta1.Probes{
+			Liveness: novav1beta1.Probe{PeriodSeconds: 20, FailureThreshold: 6},
+		}, probe(0, 20, 5, 6), probe(0, 5, 5, 3), probe(0, 10, 5, 30)},
+		{"readiness override", novav1beta1.Probes{
+			Readiness: novav1beta1.Probe{InitialDelaySeconds: 15, TimeoutSeconds: 10},
+		}, probe(0, 10, 5, 3), probe(15, 5, 10, 3), probe(0, 10, 5, 30)},
+		{"startup override", novav1beta1.Probes{
+			Startup: novav1beta1.Probe{FailureThreshold: 60},
+		}, probe(0, 10, 5, 3), probe(0, 5, 5, 3), probe(0, 10, 5, 60)},
+		// zero values are unset and keep the defaults
+		{"zero values", novav1beta1.Probes{
+			Liveness: novav1beta1.Probe{InitialDelaySeconds: 0, PeriodSeconds: 0, TimeoutSeconds: 0, FailureThreshold: 0},
+		}, probe(0, 10, 5, 3), probe(0, 5, 5, 3), probe(0, 10, 5, 30)},
+		{"all set", novav1beta1.Probes{
+			Liveness:  novav1beta1.Probe{InitialDelaySeconds: 1, PeriodSeconds: 2, TimeoutSeconds: 3, FailureThreshold: 4},
+			Readiness: novav1beta1.Probe{InitialDelaySeconds: 5, PeriodSeconds: 6, TimeoutSeconds: 7, FailureThreshold: 8},
+			Startup:   novav1beta1.Probe{InitialDelaySeconds: 9, PeriodSeconds: 10, TimeoutSeconds: 11, FailureThreshold: 12},
+		}, probe(1, 2, 3, 4), probe(5, 6, 7, 8), probe(9, 10, 11, 12)},
+	}
+	for _, test := range tests {
+		assert.Equal(test.liveness, GetLivenessProbe(test.probes), test.name)
+		assert.Equal(test.readiness, GetReadinessProbe(test.probes), test.name)
+		assert.Equal(test.startup, GetStartupProbe(test.probes), test.name)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novaapi
+
+import (
+	"strconv"
+
+	util "github.com/openstack-k8s-operators/lib-common/pkg/util"
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetWSGIProcesses - WSGI daemon processes, one per started CPU of the CPU limit if not set
+func GetWSGIProcesses(wsgi novav1beta1.NovaAPIWSGI, resources corev1.ResourceRequirements) int32 {
+	if wsgi.Processes != 0 {
+		return wsgi.Processes
+	}
+
+	if cpu, ok := resources.Limits[corev1.ResourceCPU]; ok && !cpu.IsZero() {
+		return int32((cpu.MilliValue() + 999) / 1000)
+	}
+
+	return WSGIDefaultProcesses
+}
+
+// GetWSGIEnvVars - env vars of the nova-api container substituted into the WSGI directives of httpd.conf
+func GetWSGIEnvVars(wsgi novav1beta1.NovaAPIWSGI, resources corev1.ResourceRequirements) map[string]util.EnvSetter {
+	threads := wsgi.Threads
+	if threads == 0 {
+		threads = WSGIDefaultThreads
+	}
+
+	timeout := wsgi.Timeout
+	if timeout == 0 {
+		timeout = WSGIDefaultTimeout
+	}
+
+	requestTimeout := wsgi.RequestTimeout
+	if requestTimeout == 0 {
+		requestTimeout = timeout
+	}
+
+	return map[string]util.EnvSetter{
+		"WSGIProcesses":      util.EnvValue(strconv.Itoa(int(GetWSGIProcesses(wsgi, resources)))),
+		"WSGIThreads":        util.EnvValue(strconv.Itoa(int(threads))),
+		"WSGITimeout":        util.EnvValue(strconv.Itoa(int(timeout))),
+		"WSGIRequestTimeout": util.EnvValue(strconv.Itoa(int(requestTimeout))),
+	}
+}
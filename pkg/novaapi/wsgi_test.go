@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novaapi
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetWSGIProcesses(t *testing.T) {
+	assert := assert.New(t)
+
+	cpuLimit := func(cpu string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		wsgi      novav1beta1.NovaAPIWSGI
+		resources corev1.ResourceRequirements
+		processes int32
+	}{
+		{"no limit", novav1beta1.NovaAPIWSGI{}, corev1.ResourceRequirements{}, WSGIDefaultProcesses},
+		{"memory limit only", novav1beta1.NovaAPIWSGI{}, corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}, WSGIDefaultProcesses},
+		{"cpu request only", novav1beta1.NovaAPIWSGI{}, corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		}, WSGIDefaultProcesses},
+		{"zero limit", novav1beta1.NovaAPIWSGI{}, cpuLimit("0"), WSGIDefaultProcesses},
+		{"whole cpus", novav1beta1.NovaAPIWSGI{}, cpuLimit("2"), 2},
+		// a started CPU counts as a whole one
+		{"fractional millicores", novav1beta1.NovaAPIWSGI{}, cpuLimit("1500m"), 2},
+		{"below one cpu", novav1beta1.NovaAPIWSGI{}, cpuLimit("100m"), 1},
+		{"one millicore above", novav1beta1.NovaAPIWSGI{}, cpuLimit("3001m"), 4},
+		{"fractional cpus", novav1beta1.NovaAPIWSGI{}, cpuLimit("0.5"), 1},
+		// explicit processes take precedence
+		{"processes set", novav1beta1.NovaAPIWSGI{Processes: 8}, cpuLimit("2"), 8},
+		{"processes set without limit", novav1beta1.NovaAPIWSGI{Processes: 2}, corev1.ResourceRequirements{}, 2},
+	}
+	for _, test := range tests {
+		assert.Equal(test.processes, GetWSGIProcesses(test.wsgi, test.resources), test.name)
+	}
+}
+
+func TestGetWSGIEnvVars(t *testing.T) {
+	assert := assert.New(t)
+
+	getValues := func(wsgi novav1beta1.NovaAPIWSGI, resources corev1.ResourceRequirements) map[string]string {
+		values := map[string]string{}
+		for name, setter := range GetWSGIEnvVars(wsgi, resources) {
+			env := corev1.EnvVar{}
+			setter(&env)
+			values[name] = env.Value
+		}
+		return values
+	}
+
+	// defaults
+	assert.Equal(map[string]string{
+		"WSGIProcesses":      "4",
+		"WSGIThreads":        "1",
+		"WSGITimeout":        "60",
+		"WSGIRequestTimeout": "60",
+	}, getValues(novav1beta1.NovaAPIWSGI{}, corev1.ResourceRequirements{}))
+
+	// the request timeout follows the timeout if not set
+	assert.Equal(map[string]string{
+		"WSGIProcesses":      "2",
+		"WSGIThreads":        "3",
+		"WSGITimeout":        "120",
+		"WSGIRequestTimeout": "120",
+	}, getValues(novav1beta1.NovaAPIWSGI{Threads: 3, Timeout: 120}, corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")},
+	}))
+
+	assert.Equal("30", getValues(novav1beta1.NovaAPIWSGI{RequestTimeout: 30}, corev1.ResourceRequirements{})["WSGIRequestTimeout"])
+}
@@ -29,13 +29,18 @@ import (
 )
 
 // CreateCellJob func
-func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
+func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme, novaSecret string) *batchv1.Job {
+	selectors := common.GetPasswordSelectors(cr.Spec.PasswordSelectors)
 
 	runAsUser := int64(0)
 	initVolumeMounts := common.GetInitVolumeMounts()
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(cr.Name)
 
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(cr.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(cr.Spec.DatabaseTLS)...)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-create-cell", cr.Name),
@@ -43,15 +48,19 @@ func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Jo
 			Labels:    common.GetLabels(cr.Name, AppLabel),
 		},
 		Spec: batchv1.JobSpec{
+			// keep failed pods and their logs, kubernetes retries with an exponential back-off
+			BackoffLimit: common.GetJobRetryLimit(cr.Spec.JobRetryLimit),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy:      "OnFailure",
+					RestartPolicy:      "Never",
 					ServiceAccountName: "nova",
+					PriorityClassName:  cr.Spec.PriorityClassName,
 					Volumes:            volumes,
 					Containers: []corev1.Container{
 						{
-							Name:  cr.Name + "-create-cell",
-							Image: cr.Spec.NovaConductorContainerImage,
+							Name:      cr.Name + "-create-cell",
+							Image:     cr.Spec.NovaConductorContainerImage,
+							Resources: cr.Spec.JobResources,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &runAsUser,
 							},
@@ -72,6 +81,10 @@ func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Jo
 									Name:  "DatabaseHost",
 									Value: cr.Spec.DatabaseHostname,
 								},
+								{
+									Name:  "DatabaseConnectionParams",
+									Value: common.GetDatabaseConnectionParams(cr.Spec.DatabaseTLS),
+								},
 								{
 									Name:  "ApiDatabase",
 									Value: fmt.Sprintf("%s_%s", DatabasePrefix, APIDatabase),
@@ -85,9 +98,9 @@ func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Jo
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
 											LocalObjectReference: corev1.LocalObjectReference{
-												Name: cr.Spec.NovaSecret,
+												Name: novaSecret,
 											},
-											Key: "DatabasePassword",
+											Key: selectors.Database,
 										},
 									},
 								},
@@ -98,7 +111,7 @@ func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Jo
 											LocalObjectReference: corev1.LocalObjectReference{
 												Name: cr.Spec.TransportURLSecret,
 											},
-											Key: "TransportUrl",
+											Key: selectors.TransportURL,
 										},
 									},
 								},
@@ -115,11 +128,14 @@ func CreateCellJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Jo
 		DatabaseHost:       cr.Spec.DatabaseHostname,
 		CellDatabase:       fmt.Sprintf("%s_%s", DatabasePrefix, cr.Spec.Cell),
 		APIDatabase:        fmt.Sprintf("%s_%s", DatabasePrefix, APIDatabase),
+		DatabaseTLS:        cr.Spec.DatabaseTLS,
 		TransportURLSecret: cr.Spec.TransportURLSecret,
-		NovaSecret:         cr.Spec.NovaSecret,
+		NovaSecret:         novaSecret,
 		NeutronSecret:      cr.Spec.NeutronSecret,
 		PlacementSecret:    cr.Spec.PlacementSecret,
+		PasswordSelectors:  cr.Spec.PasswordSelectors,
 		VolumeMounts:       initVolumeMounts,
+		Resources:          cr.Spec.JobResources,
 	}
 	job.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
 	controllerutil.SetControllerReference(cr, job, scheme)
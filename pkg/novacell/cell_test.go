@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCreateCellJob(t *testing.T) {
+	cr, scheme := newTestNovaCell()
+
+	job := CreateCellJob(cr, scheme, "nova-secret")
+	assert.Equal(t, "nova-cell1-create-cell", job.Name)
+	assertJobPodSpec(t, job, "openstack-control-plane", cr.Spec.JobResources)
+
+	// no requests or limits and the default priority if not set
+	cr.Spec.JobResources = corev1.ResourceRequirements{}
+	cr.Spec.PriorityClassName = ""
+	assertJobPodSpec(t, CreateCellJob(cr, scheme, "nova-secret"), "", corev1.ResourceRequirements{})
+}
@@ -23,6 +23,12 @@ const (
 	DatabasePrefix = "nova"
 	// APIDatabase -
 	APIDatabase = "api"
+	// ConductorService - DB account name of the cell nova-conductor
+	ConductorService = "conductor"
+	// MetadataService - DB account name of nova-metadata
+	MetadataService = "metadata"
+	// NoVNCProxyService - DB account name of nova-novncproxy
+	NoVNCProxyService = "novncproxy"
 	// DBSyncKollaConfig -
 	DBSyncKollaConfig = "/var/lib/config-data/merged/db-sync-config.json"
 	// CreateCellKollaConfig -
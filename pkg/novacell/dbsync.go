@@ -13,7 +13,8 @@ import (
 )
 
 // DbSyncJob func
-func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
+func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme, novaSecret string) *batchv1.Job {
+	selectors := common.GetPasswordSelectors(cr.Spec.PasswordSelectors)
 
 	runAsUser := int64(0)
 
@@ -21,6 +22,10 @@ func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
 	volumeMounts := common.GetVolumeMounts()
 	volumes := common.GetVolumes(cr.Name)
 
+	// DB CA certificate if DB TLS is enabled
+	volumeMounts = append(volumeMounts, common.GetDatabaseTLSVolumeMounts(cr.Spec.DatabaseTLS)...)
+	volumes = append(volumes, common.GetDatabaseTLSVolumes(cr.Spec.DatabaseTLS)...)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-db-sync",
@@ -28,15 +33,19 @@ func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
 			Labels:    common.GetLabels(cr.Name, AppLabel),
 		},
 		Spec: batchv1.JobSpec{
+			// keep failed pods and their logs, kubernetes retries with an exponential back-off
+			BackoffLimit: common.GetJobRetryLimit(cr.Spec.JobRetryLimit),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy:      "OnFailure",
+					RestartPolicy:      "Never",
 					ServiceAccountName: "nova",
+					PriorityClassName:  cr.Spec.PriorityClassName,
 					Volumes:            volumes,
 					Containers: []corev1.Container{
 						{
-							Name:  cr.Name + "-db-sync",
-							Image: cr.Spec.NovaConductorContainerImage,
+							Name:      cr.Name + "-db-sync",
+							Image:     cr.Spec.NovaConductorContainerImage,
+							Resources: cr.Spec.JobResources,
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser: &runAsUser,
 							},
@@ -74,9 +83,9 @@ func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
 											LocalObjectReference: corev1.LocalObjectReference{
-												Name: cr.Spec.NovaSecret,
+												Name: novaSecret,
 											},
-											Key: "DatabasePassword",
+											Key: selectors.Database,
 										},
 									},
 								},
@@ -87,7 +96,7 @@ func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
 											LocalObjectReference: corev1.LocalObjectReference{
 												Name: cr.Spec.TransportURLSecret,
 											},
-											Key: "TransportUrl",
+											Key: selectors.TransportURL,
 										},
 									},
 								},
@@ -104,11 +113,14 @@ func DbSyncJob(cr *novav1beta1.NovaCell, scheme *runtime.Scheme) *batchv1.Job {
 		DatabaseHost:       cr.Spec.DatabaseHostname,
 		CellDatabase:       fmt.Sprintf("%s_%s", DatabasePrefix, cr.Spec.Cell),
 		APIDatabase:        fmt.Sprintf("%s_%s", DatabasePrefix, APIDatabase),
+		DatabaseTLS:        cr.Spec.DatabaseTLS,
 		TransportURLSecret: cr.Spec.TransportURLSecret,
-		NovaSecret:         cr.Spec.NovaSecret,
+		NovaSecret:         novaSecret,
 		NeutronSecret:      cr.Spec.NeutronSecret,
 		PlacementSecret:    cr.Spec.PlacementSecret,
+		PasswordSelectors:  cr.Spec.PasswordSelectors,
 		VolumeMounts:       initVolumeMounts,
+		Resources:          cr.Spec.JobResources,
 	}
 	job.Spec.Template.Spec.InitContainers = common.GetCtrlInitContainer(initContainerDetails)
 	controllerutil.SetControllerReference(cr, job, scheme)
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacell
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// newTestNovaCell - cell with job resources and priority class
+func newTestNovaCell() (*novav1beta1.NovaCell, *runtime.Scheme) {
+	scheme := runtime.NewScheme()
+	_ = novav1beta1.AddToScheme(scheme)
+
+	cr := &novav1beta1.NovaCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "nova-cell1", Namespace: "openstack"},
+		Spec: novav1beta1.NovaCellSpec{
+			Cell:               "cell1",
+			TransportURLSecret: "cell1-transport",
+			JobResources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+			},
+			PriorityClassName: "openstack-control-plane",
+		},
+	}
+	return cr, scheme
+}
+
+// assertJobPodSpec - the priority class and the resources of the job containers and the init containers
+func assertJobPodSpec(t *testing.T, job *batchv1.Job, priorityClassName string, resources corev1.ResourceRequirements) {
+	assert := assert.New(t)
+
+	assert.Equal(priorityClassName, job.Spec.Template.Spec.PriorityClassName)
+	assert.NotEmpty(job.Spec.Template.Spec.Containers)
+	for _, container := range job.Spec.Template.Spec.Containers {
+		assert.Equal(resources, container.Resources, container.Name)
+	}
+	assert.NotEmpty(job.Spec.Template.Spec.InitContainers)
+	for _, container := range job.Spec.Template.Spec.InitContainers {
+		assert.Equal(resources, container.Resources, container.Name)
+	}
+}
+
+func TestDbSyncJob(t *testing.T) {
+	cr, scheme := newTestNovaCell()
+
+	job := DbSyncJob(cr, scheme, "nova-secret")
+	assert.Equal(t, "nova-cell1-db-sync", job.Name)
+	assertJobPodSpec(t, job, "openstack-control-plane", cr.Spec.JobResources)
+
+	// no requests or limits and the default priority if not set
+	cr.Spec.JobResources = corev1.ResourceRequirements{}
+	cr.Spec.PriorityClassName = ""
+	assertJobPodSpec(t, DbSyncJob(cr, scheme, "nova-secret"), "", corev1.ResourceRequirements{})
+}
@@ -16,9 +16,15 @@ limitations under the License.
 
 package novacompute
 
+import (
+	"time"
+)
+
 const (
 	// AppLabel -
 	AppLabel = "nova-compute"
 	// KollaConfig -
 	KollaConfig = "/var/lib/config-data/merged/nova_compute_config.json"
+	// ServiceStatePollInterval - interval of the os-services requests of the service state of the nodes
+	ServiceStatePollInterval = time.Minute
 )
@@ -18,12 +18,15 @@ package novacompute
 
 import (
 	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // GetInitEnvVars -
 func GetInitEnvVars(cr *novav1beta1.NovaCompute) []corev1.EnvVar {
-	return []corev1.EnvVar{
+	selectors := common.GetPasswordSelectors(cr.Spec.PasswordSelectors)
+
+	envs := []corev1.EnvVar{
 		{
 			Name: "TransportURL",
 			ValueFrom: &corev1.EnvVarSource{
@@ -31,7 +34,7 @@ func GetInitEnvVars(cr *novav1beta1.NovaCompute) []corev1.EnvVar {
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: cr.Spec.TransportURLSecret,
 					},
-					Key: "TransportUrl",
+					Key: selectors.TransportURL,
 				},
 			},
 		},
@@ -42,7 +45,7 @@ func GetInitEnvVars(cr *novav1beta1.NovaCompute) []corev1.EnvVar {
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: cr.Spec.NovaSecret,
 					},
-					Key: "NovaKeystoneAuthPassword",
+					Key: selectors.Service,
 				},
 			},
 		},
@@ -53,7 +56,7 @@ func GetInitEnvVars(cr *novav1beta1.NovaCompute) []corev1.EnvVar {
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: cr.Spec.NeutronSecret,
 					},
-					Key: "NeutronKeystoneAuthPassword",
+					Key: selectors.Neutron,
 				},
 			},
 		},
@@ -64,9 +67,19 @@ func GetInitEnvVars(cr *novav1beta1.NovaCompute) []corev1.EnvVar {
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: cr.Spec.PlacementSecret,
 					},
-					Key: "PlacementKeystoneAuthPassword",
+					Key: selectors.Placement,
 				},
 			},
 		},
 	}
+
+	envs = append(envs, common.GetMetadataProxySharedSecretEnvVar(cr.Spec.NovaSecret, selectors))
+	if cr.Spec.Notifications.TransportURLSecret != "" {
+		envs = append(envs, common.GetNotificationsTransportURLEnvVar(cr.Spec.Notifications.TransportURLSecret, selectors.TransportURL))
+	}
+	if cr.Spec.ApplicationCredentialSecret != "" {
+		envs = append(envs, common.GetApplicationCredentialEnvVars(cr.Spec.ApplicationCredentialSecret, selectors)...)
+	}
+
+	return envs
 }
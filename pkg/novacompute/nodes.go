@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacompute
+
+import (
+	"sort"
+	"strings"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetNodeStatuses - state of nova-compute on the nodes of the pods of the daemon, sorted by node name
+func GetNodeStatuses(pods []corev1.Pod, nodes map[string]*corev1.Node, currentRevision string) []novav1beta1.NovaComputeNodeStatus {
+	statuses := []novav1beta1.NovaComputeNodeStatus{}
+	for _, pod := range pods {
+		// terminating pods got replaced during a rollout
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		status := novav1beta1.NovaComputeNodeStatus{
+			NodeName:       pod.Spec.NodeName,
+			PodName:        pod.Name,
+			Ready:          isPodReady(pod),
+			NodeReady:      IsNodeReady(nodes[pod.Spec.NodeName]),
+			Revision:       pod.Labels[appsv1.DefaultDaemonSetUniqueLabelKey],
+			ContainerReady: isContainerReady(pod),
+		}
+		status.UpToDate = status.Revision != "" && status.Revision == currentRevision
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].NodeName < statuses[j].NodeName
+	})
+	return statuses
+}
+
+// SetServiceStates - state and status of the nova-compute services of the nodes in the os-services API,
+// the fields stay empty for the nodes without a service record
+func SetServiceStates(statuses []novav1beta1.NovaComputeNodeStatus, services []common.NovaService) {
+	for i := range statuses {
+		for _, service := range services {
+			if service.Binary == AppLabel && isServiceHost(service.Host, statuses[i].NodeName) {
+				statuses[i].ServiceState = service.State
+				statuses[i].ServiceStatus = service.Status
+				statuses[i].ServiceDisabledReason = service.DisabledReason
+				break
+			}
+		}
+	}
+}
+
+// isServiceHost - nova-compute runs in the host network, the host of its service record is the hostname
+// of the node, which is the node name or its short form
+func isServiceHost(host string, nodeName string) bool {
+	return host == nodeName || strings.SplitN(host, ".", 2)[0] == strings.SplitN(nodeName, ".", 2)[0]
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsNodeReady - the node is ready and schedulable
+func IsNodeReady(node *corev1.Node) bool {
+	if node == nil || node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isContainerReady - the nova-compute container is running and its readiness probe, the heartbeat
+// health check of the service, passes
+func isContainerReady(pod corev1.Pod) bool {
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Name == "nova-compute" {
+			return container.State.Running != nil && container.Ready
+		}
+	}
+	return false
+}
+
+// GetCurrentRevision - hash of the newest of the ControllerRevisions of the DaemonSet, the pods of the
+// current pod template carry it in their controller-revision-hash label
+func GetCurrentRevision(daemonSet *appsv1.DaemonSet, revisions []appsv1.ControllerRevision) string {
+	var current *appsv1.ControllerRevision
+	for i, revision := range revisions {
+		owner := metav1.GetControllerOf(&revision)
+		if owner == nil || owner.UID != daemonSet.UID {
+			continue
+		}
+		if current == nil || revision.Revision > current.Revision {
+			current = &revisions[i]
+		}
+	}
+	if current == nil {
+		return ""
+	}
+	return current.Labels[appsv1.DefaultDaemonSetUniqueLabelKey]
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacompute
+
+import (
+	"testing"
+
+	novav1beta1 "github.com/openstack-k8s-operators/nova-operator/api/v1beta1"
+	common "github.com/openstack-k8s-operators/nova-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newNode - node with the Ready condition status
+func newNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+// newPod - nova-compute pod of the revision on the node
+func newPod(name string, node string, revision string, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: revision},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "nova-compute",
+					Ready: ready,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+}
+
+func TestIsNodeReady(t *testing.T) {
+	assert := assert.New(t)
+
+	unschedulable := newNode("worker-0", corev1.ConditionTrue)
+	unschedulable.Spec.Unschedulable = true
+
+	tests := []struct {
+		name     string
+		node     *corev1.Node
+		expected bool
+	}{
+		{"ready", newNode("worker-0", corev1.ConditionTrue), true},
+		{"not ready", newNode("worker-0", corev1.ConditionFalse), false},
+		{"unknown", newNode("worker-0", corev1.ConditionUnknown), false},
+		{"cordoned", unschedulable, false},
+		{"no ready condition", &corev1.Node{}, false},
+		{"deleted", nil, false},
+	}
+	for _, test := range tests {
+		assert.Equal(test.expected, IsNodeReady(test.node), test.name)
+	}
+}
+
+func TestGetNodeStatuses(t *testing.T) {
+	assert := assert.New(t)
+
+	terminating := newPod("nova-compute-old", "worker-0", "rev1", true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+	crashing := newPod("nova-compute-c", "worker-2", "rev2", false)
+	crashing.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+
+	pods := []corev1.Pod{
+		newPod("nova-compute-b", "worker-1", "rev1", true),
+		newPod("nova-compute-a", "worker-0", "rev2", true),
+		crashing,
+		terminating,
+		// not scheduled yet
+		newPod("nova-compute-d", "", "rev2", false),
+	}
+	nodes := map[string]*corev1.Node{
+		"worker-0": newNode("worker-0", corev1.ConditionTrue),
+		"worker-1": newNode("worker-1", corev1.ConditionFalse),
+	}
+
+	statuses := GetNodeStatuses(pods, nodes, "rev2")
+	assert.Len(statuses, 3)
+
+	assert.Equal("worker-0", statuses[0].NodeName)
+	assert.Equal("nova-compute-a", statuses[0].PodName)
+	assert.True(statuses[0].Ready)
+	assert.True(statuses[0].NodeReady)
+	assert.True(statuses[0].UpToDate)
+	assert.True(statuses[0].ContainerReady)
+
+	// pod of the previous revision on a node which is not ready
+	assert.Equal("worker-1", statuses[1].NodeName)
+	assert.Equal("rev1", statuses[1].Revision)
+	assert.False(statuses[1].UpToDate)
+	assert.False(statuses[1].NodeReady)
+
+	// unknown node, nova-compute not running
+	assert.Equal("worker-2", statuses[2].NodeName)
+	assert.True(statuses[2].UpToDate)
+	assert.False(statuses[2].NodeReady)
+	assert.False(statuses[2].ContainerReady)
+
+	assert.Empty(GetNodeStatuses(nil, nodes, "rev2"))
+}
+
+func TestSetServiceStates(t *testing.T) {
+	assert := assert.New(t)
+
+	statuses := []novav1beta1.NovaComputeNodeStatus{
+		{NodeName: "worker-0"},
+		{NodeName: "worker-1.example.com"},
+		{NodeName: "worker-2"},
+		{NodeName: "worker-3"},
+	}
+	services := []common.NovaService{
+		{Binary: "nova-compute", Host: "worker-0", Status: "enabled", State: "up"},
+		// the hostname is the short form of the node name
+		{Binary: "nova-compute", Host: "worker-1", Status: "disabled", State: "down", DisabledReason: "maintenance"},
+		// the hostname is the FQDN of the node name
+		{Binary: "nova-compute", Host: "worker-2.example.com", Status: "enabled", State: "down"},
+		// service of another binary on the node
+		{Binary: "nova-conductor", Host: "worker-3", Status: "enabled", State: "up"},
+	}
+
+	SetServiceStates(statuses, services)
+	assert.Equal([]novav1beta1.NovaComputeNodeStatus{
+		{NodeName: "worker-0", ServiceState: "up", ServiceStatus: "enabled"},
+		{NodeName: "worker-1.example.com", ServiceState: "down", ServiceStatus: "disabled", ServiceDisabledReason: "maintenance"},
+		{NodeName: "worker-2", ServiceState: "down", ServiceStatus: "enabled"},
+		// no service record of nova-compute
+		{NodeName: "worker-3"},
+	}, statuses)
+
+	// unknown without the services
+	statuses = []novav1beta1.NovaComputeNodeStatus{{NodeName: "worker-0"}}
+	SetServiceStates(statuses, nil)
+	assert.Equal([]novav1beta1.NovaComputeNodeStatus{{NodeName: "worker-0"}}, statuses)
+}
+
+func TestGetCurrentRevision(t *testing.T) {
+	assert := assert.New(t)
+
+	daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nova-compute", UID: "ds-uid"}}
+	isController := true
+	newRevision := func(hash string, revision int64, owner types.UID) appsv1.ControllerRevision {
+		return appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:          map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash},
+				OwnerReferences: []metav1.OwnerReference{{UID: owner, Controller: &isController}},
+			},
+			Revision: revision,
+		}
+	}
+	revisions := []appsv1.ControllerRevision{
+		newRevision("rev1", 1, "ds-uid"),
+		newRevision("rev3", 3, "ds-uid"),
+		newRevision("rev2", 2, "ds-uid"),
+		// revision of another DaemonSet
+		newRevision("other", 4, "other-uid"),
+	}
+
+	assert.Equal("rev3", GetCurrentRevision(daemonSet, revisions))
+	assert.Equal("", GetCurrentRevision(daemonSet, nil))
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacomputestack
+
+const (
+	// AppLabel -
+	AppLabel = "nova-compute-stack"
+	// LibvirtdSuffix - suffix of the name of the Libvirtd CR of a stack
+	LibvirtdSuffix = "libvirtd"
+	// VirtlogdSuffix - suffix of the name of the Virtlogd CR of a stack
+	VirtlogdSuffix = "virtlogd"
+	// IscsidSuffix - suffix of the name of the Iscsid CR of a stack
+	IscsidSuffix = "iscsid"
+	// NovaMigrationTargetSuffix - suffix of the name of the NovaMigrationTarget CR of a stack
+	NovaMigrationTargetSuffix = "migration-target"
+	// NovaComputeSuffix - suffix of the name of the NovaCompute CR of a stack
+	NovaComputeSuffix = "compute"
+	// HypervisorLabel - node label set to the name of the stack on the nodes libvirtd and virtlogd are
+	// ready on, the node selector of nova-compute. Prefixed with the namespace of the stack by
+	// GetHypervisorLabel, stacks in different namespaces can have the same name
+	HypervisorLabel = "nova.openstack.org/hypervisor"
+	// Finalizer - removes the hypervisor labels of the stack from the nodes on deletion
+	Finalizer = "nova.openstack.org/hypervisor-labels"
+)
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacomputestack
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetHypervisorLabel - key of the hypervisor label of the stacks in the namespace
+func GetHypervisorLabel(namespace string) string {
+	return fmt.Sprintf("%s.%s", namespace, HypervisorLabel)
+}
+
+// GetReadyNodes - names of the nodes with a ready pod
+func GetReadyNodes(pods []corev1.Pod) map[string]bool {
+	nodes := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				nodes[pod.Spec.NodeName] = true
+			}
+		}
+	}
+	return nodes
+}
+
+// GetScheduledNodes - names of the nodes with a pod
+func GetScheduledNodes(pods []corev1.Pod) map[string]bool {
+	nodes := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodes[pod.Spec.NodeName] = true
+		}
+	}
+	return nodes
+}
+
+// GetHypervisorNodes - the nodes libvirtd and virtlogd are ready on. Nodes which were hypervisor
+// nodes before stay in the list while libvirtd is scheduled on them, so a restart of libvirtd
+// does not evict nova-compute from the node.
+func GetHypervisorNodes(current []string, libvirtdPods []corev1.Pod, virtlogdPods []corev1.Pod) []string {
+	libvirtdReady := GetReadyNodes(libvirtdPods)
+	virtlogdReady := GetReadyNodes(virtlogdPods)
+	libvirtdScheduled := GetScheduledNodes(libvirtdPods)
+
+	nodes := map[string]bool{}
+	for _, node := range current {
+		if libvirtdScheduled[node] {
+			nodes[node] = true
+		}
+	}
+	for node := range libvirtdReady {
+		if virtlogdReady[node] {
+			nodes[node] = true
+		}
+	}
+
+	hypervisorNodes := []string{}
+	for node := range nodes {
+		hypervisorNodes = append(hypervisorNodes, node)
+	}
+	sort.Strings(hypervisorNodes)
+	return hypervisorNodes
+}
+
+// GetHypervisorLabelChanges - nodes to set the hypervisor label on and to remove it from, to get from the
+// labeled nodes to the hypervisor nodes
+func GetHypervisorLabelChanges(labeledNodes []string, hypervisorNodes []string) ([]string, []string) {
+	labeled := map[string]bool{}
+	for _, node := range labeledNodes {
+		labeled[node] = true
+	}
+	hypervisor := map[string]bool{}
+	for _, node := range hypervisorNodes {
+		hypervisor[node] = true
+	}
+
+	add := []string{}
+	for _, node := range hypervisorNodes {
+		if !labeled[node] {
+			add = append(add, node)
+		}
+	}
+	remove := []string{}
+	for _, node := range labeledNodes {
+		if !hypervisor[node] {
+			remove = append(remove, node)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
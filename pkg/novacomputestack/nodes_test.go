@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package novacomputestack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// newPod - pod of a daemon on the node, empty for an unscheduled pod
+func newPod(node string, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestGetHypervisorNodes(t *testing.T) {
+	assert := assert.New(t)
+
+	terminating := newPod("worker-1", true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+
+	tests := []struct {
+		name         string
+		current      []string
+		libvirtdPods []corev1.Pod
+		virtlogdPods []corev1.Pod
+		expected     []string
+	}{
+		{
+			name:     "no pods",
+			expected: []string{},
+		},
+		{
+			name:         "libvirtd and virtlogd ready",
+			libvirtdPods: []corev1.Pod{newPod("worker-1", true), newPod("worker-0", true)},
+			virtlogdPods: []corev1.Pod{newPod("worker-0", true), newPod("worker-1", true)},
+			expected:     []string{"worker-0", "worker-1"},
+		},
+		{
+			name:         "virtlogd not ready",
+			libvirtdPods: []corev1.Pod{newPod("worker-0", true), newPod("worker-1", true)},
+			virtlogdPods: []corev1.Pod{newPod("worker-0", true), newPod("worker-1", false)},
+			expected:     []string{"worker-0"},
+		},
+		{
+			name:         "libvirtd not scheduled",
+			libvirtdPods: []corev1.Pod{newPod("", false)},
+			virtlogdPods: []corev1.Pod{newPod("worker-0", true)},
+			expected:     []string{},
+		},
+		{
+			name:         "terminating libvirtd",
+			libvirtdPods: []corev1.Pod{terminating},
+			virtlogdPods: []corev1.Pod{newPod("worker-1", true)},
+			expected:     []string{},
+		},
+		{
+			name:         "hypervisor node kept while libvirtd restarts",
+			current:      []string{"worker-0"},
+			libvirtdPods: []corev1.Pod{newPod("worker-0", false)},
+			virtlogdPods: []corev1.Pod{newPod("worker-0", false)},
+			expected:     []string{"worker-0"},
+		},
+		{
+			name:         "hypervisor node dropped once libvirtd is gone",
+			current:      []string{"worker-0", "worker-1"},
+			libvirtdPods: []corev1.Pod{newPod("worker-1", true)},
+			virtlogdPods: []corev1.Pod{newPod("worker-0", true), newPod("worker-1", true)},
+			expected:     []string{"worker-1"},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(test.expected, GetHypervisorNodes(test.current, test.libvirtdPods, test.virtlogdPods), test.name)
+	}
+}
+
+func TestGetHypervisorLabelChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name            string
+		labeledNodes    []string
+		hypervisorNodes []string
+		add             []string
+		remove          []string
+	}{
+		{"no nodes", nil, nil, []string{}, []string{}},
+		{"new hypervisor nodes", nil, []string{"worker-1", "worker-0"}, []string{"worker-0", "worker-1"}, []string{}},
+		{"unchanged", []string{"worker-0"}, []string{"worker-0"}, []string{}, []string{}},
+		{"node replaced", []string{"worker-0", "worker-1"}, []string{"worker-1", "worker-2"}, []string{"worker-2"}, []string{"worker-0"}},
+		{"all nodes removed", []string{"worker-0"}, []string{}, []string{}, []string{"worker-0"}},
+	}
+	for _, test := range tests {
+		add, remove := GetHypervisorLabelChanges(test.labeledNodes, test.hypervisorNodes)
+		assert.Equal(test.add, add, test.name)
+		assert.Equal(test.remove, remove, test.name)
+	}
+}
+
+func TestGetHypervisorLabel(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("openstack.nova.openstack.org/hypervisor", GetHypervisorLabel("openstack"))
+	// stacks of the same name in different namespaces get different labels
+	assert.NotEqual(GetHypervisorLabel("openstack"), GetHypervisorLabel("openstack-edge"))
+	assert.Empty(validation.IsQualifiedName(GetHypervisorLabel("openstack")))
+}
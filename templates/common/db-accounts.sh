@@ -0,0 +1,67 @@
+#!/bin/bash
+#
+# Copyright 2020 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+set -e
+
+# Creates the DB accounts of the services on the DB servers with the root password and grants them
+# their databases. The passwords of the accounts are set again on every run, accounts with the
+# DatabaseUserPrefix which are not listed, e.g. the ones of a previous NovaSecret, get dropped.
+#
+# DatabaseServers lists per DB server the accounts, the passwords are read from the named env vars:
+# [{"host": "openstack", "adminPassword": "DatabaseAdminPassword0",
+#   "accounts": [{"user": "nova_api_api_1a2b3c4d", "password": "DatabasePassword1", "databases": ["nova_api"]}]}]
+export DatabaseServers=${DatabaseServers:?"Please specify a DatabaseServers variable."}
+export DatabaseUserPrefix=${DatabaseUserPrefix:?"Please specify a DatabaseUserPrefix variable."}
+export DatabaseTLSMode=${DatabaseTLSMode:-""}
+export DatabaseCA=${DatabaseCA:-""}
+
+python3 - <<'PYTHON'
+import json
+import os
+
+import pymysql
+
+prefix = os.environ['DatabaseUserPrefix']
+ssl = None
+if os.environ['DatabaseTLSMode']:
+    ssl = {'ca': os.environ['DatabaseCA'],
+           'check_hostname': os.environ['DatabaseTLSMode'] == 'verify'}
+
+for server in json.loads(os.environ['DatabaseServers']):
+    conn = pymysql.connect(host=server['host'], user='root',
+                           password=os.environ[server['adminPassword']],
+                           ssl=ssl, autocommit=True)
+    with conn.cursor() as cursor:
+        users = set()
+        for account in server['accounts']:
+            user = account['user']
+            password = os.environ[account['password']]
+            users.add(user)
+            print('ensuring DB account %s on %s' % (user, server['host']))
+            cursor.execute("CREATE USER IF NOT EXISTS %s@'%%' IDENTIFIED BY %s", (user, password))
+            cursor.execute("ALTER USER %s@'%%' IDENTIFIED BY %s", (user, password))
+            for database in account['databases']:
+                cursor.execute("GRANT ALL PRIVILEGES ON `%s`.* TO %%s@'%%%%'" % database.replace('`', '``'), (user,))
+
+        cursor.execute("SELECT User FROM mysql.user WHERE Host = '%%' AND User LIKE %s",
+                       (prefix.replace('_', '\\_') + '%',))
+        for (user,) in cursor.fetchall():
+            if isinstance(user, bytes):
+                user = user.decode()
+            if user not in users:
+                print('dropping DB account %s on %s' % (user, server['host']))
+                cursor.execute("DROP USER %s@'%%'", (user,))
+    conn.close()
+PYTHON
@@ -0,0 +1,89 @@
+#!/bin/bash
+#
+# Copyright 2020 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+set -e
+
+# Health check of the RPC services used by the liveness, readiness and startup probes.
+# The service is alive if its process has an established connection to the message
+# bus. It is ready if in addition the heartbeat of its service record, updated every
+# report_interval, is not older than service_down_time. The liveness check does not
+# access the DB, an outage of the DB must not restart the services.
+export BINARY=${1:?"Please specify the service binary, e.g. nova-conductor."}
+CHECK=${2:-readiness}
+if [ "${CHECK}" != "liveness" ] && [ "${CHECK}" != "readiness" ]; then
+  echo "Unknown check ${CHECK}, expected liveness or readiness"
+  exit 1
+fi
+PROC_ROOT=${PROC_ROOT:-/proc}
+
+# socket inodes of the service process
+inodes=""
+for pid in $(pgrep -f "/usr/bin/${BINARY}"); do
+  inodes="${inodes} $(find ${PROC_ROOT}/${pid}/fd -lname 'socket:*' -printf '%l\n' 2>/dev/null | sed 's/socket:\[\([0-9]*\)\]/\1/')"
+done
+if [ -z "${inodes// }" ]; then
+  echo "${BINARY} is not running"
+  exit 1
+fi
+
+# established (01) connection to the AMQP port 5672 (1628) or 5671 (1627) on one of the sockets
+connected=0
+for inode in ${inodes}; do
+  if awk -v inode=${inode} '$4 == "01" && $10 == inode && $3 ~ /:162[78]$/ { found=1 } END { exit !found }' ${PROC_ROOT}/net/tcp ${PROC_ROOT}/net/tcp6; then
+    connected=1
+    break
+  fi
+done
+if [ ${connected} -eq 0 ]; then
+  echo "${BINARY} is not connected to the message bus"
+  exit 1
+fi
+
+if [ "${CHECK}" = "liveness" ]; then
+  exit 0
+fi
+
+# the compute has no DB access, its service record is read through the conductor
+python3 - ${BINARY} <<'PYTHON'
+import os
+import sys
+
+from oslo_utils import timeutils
+
+import nova.conf
+from nova.conductor import rpcapi as conductor_rpcapi
+from nova import config
+from nova import context
+from nova import objects
+from nova.objects import base as objects_base
+
+CONF = nova.conf.CONF
+
+binary = sys.argv[1]
+args = [binary, '--config-file', '/etc/nova/nova.conf']
+if os.path.isdir('/etc/nova/nova.conf.d'):
+    args += ['--config-dir', '/etc/nova/nova.conf.d']
+config.parse_args(args)
+objects.register_all()
+if binary == 'nova-compute':
+    objects_base.NovaObject.indirection_api = conductor_rpcapi.ConductorAPI()
+
+service = objects.Service.get_by_args(context.get_admin_context(), CONF.host, binary)
+heartbeat = service.updated_at or service.created_at
+age = timeutils.delta_seconds(heartbeat, timeutils.utcnow(with_timezone=True))
+if age > CONF.service_down_time:
+    print('%s heartbeat of host %s is %d seconds old' % (binary, CONF.host, age))
+    sys.exit(1)
+PYTHON
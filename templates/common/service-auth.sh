@@ -0,0 +1,35 @@
+#!/bin/bash
+#
+# Copyright 2020 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+set -e
+
+# Sets the credentials the nova service authenticates with in the keystone_authtoken and service_user
+# sections of the config file. The application credential replaces the password if set.
+CONFIG=${1:?"Please specify the config file, e.g. /var/lib/config-data/merged/nova.conf."}
+export NovaKeystoneAuthPassword=${NovaKeystoneAuthPassword:?"Please specify a NovaKeystoneAuthPassword variable."}
+export ApplicationCredentialID=${ApplicationCredentialID:-""}
+export ApplicationCredentialSecret=${ApplicationCredentialSecret:-""}
+
+crudini --set ${CONFIG} service_user password $NovaKeystoneAuthPassword
+if [ -n "$ApplicationCredentialID" ]; then
+  for section in keystone_authtoken service_user; do
+    crudini --set ${CONFIG} $section auth_type v3applicationcredential
+    crudini --set ${CONFIG} $section application_credential_id $ApplicationCredentialID
+    crudini --set ${CONFIG} $section application_credential_secret $ApplicationCredentialSecret
+    for option in password username project_name project_domain_name user_domain_name; do
+      crudini --del ${CONFIG} $section $option
+    done
+  done
+fi
@@ -18,13 +18,16 @@ set -ex
 export DatabasePassword=${DatabasePassword:?"Please specify a DatabasePassword variable."}
 export DatabaseHost=${DatabaseHost:?"Please specify a DatabaseHost variable."}
 export CellDatabase=${CellDatabase:-"nova_cell0"}
-export DatabaseConnection="mysql+pymysql://$CellDatabase:$DatabasePassword@$DatabaseHost/$CellDatabase"
+export DatabaseConnectionParams=${DatabaseConnectionParams:-""}
+# the services read the cell0 DB with the credentials of their own DB account
+export DatabaseConnection="{scheme}://{username}:{password}@$DatabaseHost/$CellDatabase$DatabaseConnectionParams"
 
 # Bootstrap and exit if KOLLA_BOOTSTRAP variable is set. This catches all cases
 # of the KOLLA_BOOTSTRAP variable being set, including empty.
 if [[ "${!KOLLA_BOOTSTRAP[@]}" ]]; then
     nova-manage api_db sync
     nova-manage cell_v2 map_cell0 --database_connection "${DatabaseConnection}"
+    nova-manage cell_v2 update_cell --cell_uuid 00000000-0000-0000-0000-000000000000 --database_connection "${DatabaseConnection}"
     nova-manage db sync
     nova-manage db online_data_migrations
     exit 0
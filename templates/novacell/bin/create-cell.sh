@@ -18,12 +18,20 @@ set -x
 export Cell=${Cell:-"cell1"}
 export DatabaseHost=${DatabaseHost:?"Please specify a DatabaseHost variable."}
 export CellDatabase=${CellDatabase:-"nova_cell1"}
+export DatabaseConnectionParams=${DatabaseConnectionParams:-""}
 export TransportURL=${TransportURL:?"Please specify a TransportURL variable."}
 
-isCell=$(nova-manage cell_v2 list_cells | grep ${Cell})
-if [ -z "${isCell}" ]; then
+# the services read the cell DB with the credentials of their own DB account
+export DatabaseConnection="{scheme}://{username}:{password}@${DatabaseHost}/${CellDatabase}${DatabaseConnectionParams}"
+
+cellUUID=$(nova-manage cell_v2 list_cells | awk -v cell=${Cell} '$2 == cell { print $4 }')
+if [ -z "${cellUUID}" ]; then
   echo "creating cell ${Cell}!"
   nova-manage cell_v2 create_cell --name ${Cell} \
-    --database_connection "{scheme}://${CellDatabase}:{password}@${DatabaseHost}/${CellDatabase}" \
+    --database_connection "${DatabaseConnection}" \
+    --transport-url "${TransportURL}"
+else
+  nova-manage cell_v2 update_cell --cell_uuid ${cellUUID} \
+    --database_connection "${DatabaseConnection}" \
     --transport-url "${TransportURL}"
 fi
@@ -22,10 +22,16 @@ set -ex
 export ApiDatabase=${ApiDatabase:-"nova_api"}
 export CellDatabase=${CellDatabase:-"nova_cell0"}
 export DatabaseHost=${DatabaseHost:?"Please specify a DatabaseHost variable."}
+export DatabaseConnectionParams=${DatabaseConnectionParams:-""}
+export DatabaseUser=${DatabaseUser:-""}
 export PlacementKeystoneAuthPassword=${PlacementKeystoneAuthPassword:?"Please specify a PlacementKeystoneAuthPassword variable."}
 export NeutronKeystoneAuthPassword=${NeutronKeystoneAuthPassword:?"Please specify a NeutronKeystoneAuthPassword variable."}
 export DatabasePassword=${DatabasePassword:?"Please specify a DatabasePassword variable."}
 export TransportURL=${TransportURL:?"Please specify a TransportURL variable."}
+export NotificationsTransportURL=${NotificationsTransportURL:-""}
+export MetadataProxySharedSecret=${MetadataProxySharedSecret:-""}
+export ApplicationCredentialID=${ApplicationCredentialID:-""}
+export ApplicationCredentialSecret=${ApplicationCredentialSecret:-""}
 export NovaKeystoneAuthPassword=${NovaKeystoneAuthPassword:?"Please specify a NovaKeystoneAuthPassword variable."}
 
 # expect that the common.sh is in the same dir as the calling script
@@ -44,7 +50,24 @@ done
 # set secrets
 crudini --set /var/lib/config-data/merged/nova.conf DEFAULT transport_url $TransportURL
 crudini --set /var/lib/config-data/merged/nova.conf keystone_authtoken password $NovaKeystoneAuthPassword
-crudini --set /var/lib/config-data/merged/nova.conf database connection mysql+pymysql://$CellDatabase:$DatabasePassword@$DatabaseHost/$CellDatabase
-crudini --set /var/lib/config-data/merged/nova.conf api_database connection mysql+pymysql://$ApiDatabase:$DatabasePassword@$DatabaseHost/$ApiDatabase
+# services connect with their own DB account, jobs with the account named like the database
+CellDatabaseUser=${DatabaseUser:-$CellDatabase}
+ApiDatabaseUser=${DatabaseUser:-$ApiDatabase}
+crudini --set /var/lib/config-data/merged/nova.conf database connection mysql+pymysql://$CellDatabaseUser:$DatabasePassword@$DatabaseHost/$CellDatabase$DatabaseConnectionParams
+crudini --set /var/lib/config-data/merged/nova.conf api_database connection mysql+pymysql://$ApiDatabaseUser:$DatabasePassword@$DatabaseHost/$ApiDatabase$DatabaseConnectionParams
 crudini --set /var/lib/config-data/merged/nova.conf neutron password $NeutronKeystoneAuthPassword
 crudini --set /var/lib/config-data/merged/nova.conf placement password $PlacementKeystoneAuthPassword
+
+# notifications use the TransportURL if no separate notifications bus is set
+if [ -n "$NotificationsTransportURL" ]; then
+  crudini --set /var/lib/config-data/merged/nova.conf oslo_messaging_notifications transport_url $NotificationsTransportURL
+fi
+
+# the metadata requests proxied by neutron are signed with the shared secret if set
+if [ -n "$MetadataProxySharedSecret" ]; then
+  crudini --set /var/lib/config-data/merged/nova.conf neutron service_metadata_proxy true
+  crudini --set /var/lib/config-data/merged/nova.conf neutron metadata_proxy_shared_secret $MetadataProxySharedSecret
+fi
+
+# the nova service authenticates with the application credential instead of the password if set
+${SCRIPTPATH}/service-auth.sh /var/lib/config-data/merged/nova.conf
@@ -24,6 +24,10 @@ export NovaKeystoneAuthPassword=${NovaKeystoneAuthPassword:?"Please specify a No
 export PlacementKeystoneAuthPassword=${PlacementKeystoneAuthPassword:?"Please specify a PlacementKeystoneAuthPassword variable."}
 export NeutronKeystoneAuthPassword=${NeutronKeystoneAuthPassword:?"Please specify a NeutronKeystoneAuthPassword variable."}
 export TransportURL=${TransportURL:?"Please specify a TransportURL variable."}
+export NotificationsTransportURL=${NotificationsTransportURL:-""}
+export MetadataProxySharedSecret=${MetadataProxySharedSecret:-""}
+export ApplicationCredentialID=${ApplicationCredentialID:-""}
+export ApplicationCredentialSecret=${ApplicationCredentialSecret:-""}
 
 # expect that the common.sh is in the same dir as the calling script
 SCRIPTPATH="$( cd "$(dirname "$0")" >/dev/null 2>&1 ; pwd -P )"
@@ -54,4 +58,18 @@ crudini --set /var/lib/config-data/merged/nova.conf vnc server_proxyclient_addre
 crudini --set /var/lib/config-data/merged/nova.conf DEFAULT transport_url $TransportURL
 crudini --set /var/lib/config-data/merged/nova.conf keystone_authtoken password $NovaKeystoneAuthPassword
 crudini --set /var/lib/config-data/merged/nova.conf neutron password $NeutronKeystoneAuthPassword
-crudini --set /var/lib/config-data/merged/nova.conf placement password $PlacementKeystoneAuthPassword
\ No newline at end of file
+crudini --set /var/lib/config-data/merged/nova.conf placement password $PlacementKeystoneAuthPassword
+
+# notifications use the TransportURL if no separate notifications bus is set
+if [ -n "$NotificationsTransportURL" ]; then
+  crudini --set /var/lib/config-data/merged/nova.conf oslo_messaging_notifications transport_url $NotificationsTransportURL
+fi
+
+# the metadata requests proxied by neutron are signed with the shared secret if set
+if [ -n "$MetadataProxySharedSecret" ]; then
+  crudini --set /var/lib/config-data/merged/nova.conf neutron service_metadata_proxy true
+  crudini --set /var/lib/config-data/merged/nova.conf neutron metadata_proxy_shared_secret $MetadataProxySharedSecret
+fi
+
+# the nova service authenticates with the application credential instead of the password if set
+${SCRIPTPATH}/service-auth.sh /var/lib/config-data/merged/nova.conf
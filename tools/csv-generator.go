@@ -86,19 +86,17 @@ Install and configure OpenStack Nova containers.
 		data.Verbosity,
 		data.ImagePullPolicy)
 
-	//clusterRules := getOperatorClusterRules()
+	clusterRules := getOperatorClusterRules()
 	rules := getOperatorRules()
 	serviceRules := getServiceRules()
 
 	strategySpec := csvv1.StrategyDetailsDeployment{
-		/*
-			ClusterPermissions: []csvv1.StrategyDeploymentPermissions{
-				{
-					ServiceAccountName: "nova-operator",
-					Rules:              *clusterRules,
-				},
+		ClusterPermissions: []csvv1.StrategyDeploymentPermissions{
+			{
+				ServiceAccountName: "nova-operator",
+				Rules:              *clusterRules,
 			},
-		*/
+		},
 		Permissions: []csvv1.StrategyDeploymentPermissions{
 			{
 				ServiceAccountName: "nova-operator",
@@ -222,6 +220,13 @@ Install and configure OpenStack Nova containers.
 						DisplayName: "Nova Compute",
 						Description: "NovaCompute is the Schema for the novacomputes API",
 					},
+					{
+						Name:        "novacomputestacks.nova.openstack.org",
+						Version:     "v1beta1",
+						Kind:        "NovaComputeStack",
+						DisplayName: "Nova Compute Stack",
+						Description: "NovaComputeStack is the Schema for the novacomputestacks API",
+					},
 					{
 						Name:        "novamigrationtargets.nova.openstack.org",
 						Version:     "v1beta1",
@@ -291,6 +296,26 @@ Install and configure OpenStack Nova containers.
 	}, nil
 }
 
+func getOperatorClusterRules() *[]rbacv1.PolicyRule {
+	return &[]rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"nodes",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"patch",
+				"update",
+			},
+		},
+	}
+}
+
 func getOperatorRules() *[]rbacv1.PolicyRule {
 	return &[]rbacv1.PolicyRule{
 		{
@@ -320,6 +345,7 @@ func getOperatorRules() *[]rbacv1.PolicyRule {
 				"daemonsets",
 				"replicasets",
 				"statefulsets",
+				"controllerrevisions",
 			},
 			Verbs: []string{
 				"*",
@@ -402,6 +428,7 @@ func getOperatorRules() *[]rbacv1.PolicyRule {
 				"libvirtds",
 				"iscsids",
 				"novamigrationtargets",
+				"novacomputestacks",
 				"nova",
 				"novaapis",
 				"novacells",
@@ -437,7 +464,11 @@ func getOperatorRules() *[]rbacv1.PolicyRule {
 			},
 			Verbs: []string{
 				"get",
+				"list",
+				"watch",
 				"create",
+				"update",
+				"delete",
 			},
 		},
 	}